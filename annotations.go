@@ -0,0 +1,82 @@
+// Package diffy provides GitHub Actions workflow-command annotations
+package diffy
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// NewGitHubAnnotationsReporter writes findings as GitHub Actions workflow commands, so they show up as
+// inline annotations on the workflow run and pull request diff. Required findings become errors and
+// optional ones warnings, grouped per module. No token is needed.
+func NewGitHubAnnotationsReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		return WriteGitHubAnnotations(w, report.Findings)
+	})
+}
+
+func WriteGitHubAnnotations(w io.Writer, findings []ValidationFinding) error {
+	sorted := slices.Clone(findings)
+	slices.SortStableFunc(sorted, func(x, y ValidationFinding) int {
+		return cmp.Compare(x.SubmoduleName, y.SubmoduleName)
+	})
+
+	for _, group := range groupFindings(sorted, func(f ValidationFinding) string { return f.SubmoduleName }) {
+		name := "root module"
+		if group[0].SubmoduleName != "" {
+			name = "module " + group[0].SubmoduleName
+		}
+
+		if _, err := fmt.Fprintf(w, "::group::diffy: %s (%d %s)\n", escapeWorkflowData(name), len(group), plural(len(group), "finding", "findings")); err != nil {
+			return err
+		}
+
+		for _, finding := range group {
+			if _, err := io.WriteString(w, formatAnnotation(finding)+"\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(w, "::endgroup::\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatAnnotation(finding ValidationFinding) string {
+	command := "warning"
+	if finding.Required {
+		command = "error"
+	}
+
+	var properties []string
+	if file := finding.Location.RelativeFilename(); file != "" {
+		properties = append(properties, "file="+escapeWorkflowProperty(file))
+		if finding.Location.Start.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", finding.Location.Start.Line))
+		}
+		if finding.Location.End.Line > 0 {
+			properties = append(properties, fmt.Sprintf("endLine=%d", finding.Location.End.Line))
+		}
+	}
+	properties = append(properties, "title="+escapeWorkflowProperty(RuleForFinding(finding).Name))
+
+	return fmt.Sprintf("::%s %s::%s", command, strings.Join(properties, ","), escapeWorkflowData(FormatFinding(finding)))
+}
+
+var (
+	workflowDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	workflowPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeWorkflowData(s string) string {
+	return workflowDataEscaper.Replace(s)
+}
+
+func escapeWorkflowProperty(s string) string {
+	return workflowPropertyEscaper.Replace(s)
+}
@@ -0,0 +1,68 @@
+package diffy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	findings := []ValidationFinding{
+		{
+			ResourceType:  "azurerm_subnet",
+			Path:          "root",
+			Name:          "delegation",
+			IsBlock:       true,
+			SubmoduleName: "network",
+		},
+		{
+			ResourceType: "azurerm_key_vault",
+			Path:         "root",
+			Name:         "sku_name",
+			Required:     true,
+			Location: SourceRange{
+				Filename: "main.tf",
+				Start:    SourcePos{Line: 4, Column: 1},
+				End:      SourcePos{Line: 12, Column: 2},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubAnnotations(&buf, findings); err != nil {
+		t.Fatalf("WriteGitHubAnnotations returned error: %v", err)
+	}
+
+	want := []string{
+		"::group::diffy: root module (1 finding)",
+		"::error file=main.tf,line=4,endLine=12,title=MissingRequiredProperty::azurerm_key_vault: missing required property sku_name in root (resource)",
+		"::endgroup::",
+		"::group::diffy: module network (1 finding)",
+		"::warning title=MissingOptionalBlock::azurerm_subnet: missing optional block delegation in root in submodule network (resource)",
+		"::endgroup::",
+	}
+	if diff := cmp.Diff(want, strings.Split(strings.TrimSpace(buf.String()), "\n")); diff != "" {
+		t.Fatalf("annotations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkflowCommandEscaping(t *testing.T) {
+	if got := escapeWorkflowData("50%\nnext"); got != "50%25%0Anext" {
+		t.Errorf("escapeWorkflowData() = %q", got)
+	}
+	if got := escapeWorkflowProperty("a:b,c"); got != "a%3Ab%2Cc" {
+		t.Errorf("escapeWorkflowProperty() = %q", got)
+	}
+}
+
+func TestGitHubAnnotationsReporterNoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewGitHubAnnotationsReporter(&buf).Report(NewReport(&SchemaValidatorOptions{}, nil, nil)); err != nil {
+		t.Fatalf("Report returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output without findings, got %q", buf.String())
+	}
+}
@@ -2,14 +2,21 @@ package diffy
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/agext/levenshtein"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 func NewBlockData() BlockData {
 	return BlockData{
-		Properties:    make(map[string]bool),
+		Properties:    make(map[string]ValueClass),
+		Expressions:   make(map[string]hclsyntax.Expression),
 		StaticBlocks:  make(map[string][]*ParsedBlock),
 		DynamicBlocks: make(map[string]*ParsedBlock),
 		IgnoreChanges: []string{},
@@ -17,8 +24,9 @@ func NewBlockData() BlockData {
 }
 
 func (blockData *BlockData) ParseAttributes(body *hclsyntax.Body) {
-	for name := range body.Attributes {
-		blockData.Properties[name] = true
+	for name, attribute := range body.Attributes {
+		blockData.Properties[name] = classifyExpression(attribute.Expr)
+		blockData.Expressions[name] = attribute.Expr
 	}
 }
 
@@ -29,10 +37,11 @@ func (blockData *BlockData) ParseBlocks(body *hclsyntax.Body) {
 			blockData.parseLifecycle(block.Body)
 		case "dynamic":
 			if len(block.Labels) == 1 {
-				blockData.parseDynamicBlock(block.Body, block.Labels[0])
+				blockData.parseDynamicBlock(block, block.Labels[0])
 			}
 		default:
 			parsed := ParseSyntaxBody(block.Body)
+			parsed.Data.Range = NewSourceRange(block.Range())
 			blockData.StaticBlocks[block.Type] = append(blockData.StaticBlocks[block.Type], parsed)
 		}
 	}
@@ -58,10 +67,11 @@ func extractIgnoreChanges(attribute *hclsyntax.Attribute) []string {
 	return extractIgnoreChangesFromExpr(attribute.Expr)
 }
 
-func (blockData *BlockData) parseDynamicBlock(body *hclsyntax.Body, name string) {
-	blockData.Properties[name] = true
-	contentBlock := findContentBlockInBody(body)
+func (blockData *BlockData) parseDynamicBlock(block *hclsyntax.Block, name string) {
+	blockData.Properties[name] = ValueComplex
+	contentBlock := findContentBlockInBody(block.Body)
 	parsed := ParseSyntaxBody(contentBlock)
+	parsed.Data.Range = NewSourceRange(block.Range())
 	if existing := blockData.DynamicBlocks[name]; existing != nil {
 		mergeBlocks(existing, parsed)
 	} else {
@@ -87,8 +97,11 @@ func extractIgnoreChangesFromExpr(expr hclsyntax.Expression) []string {
 		}
 		return results
 	case *hclsyntax.ScopeTraversalExpr:
-		if len(e.Traversal) > 0 {
-			return []string{e.Traversal.RootName()}
+		if len(e.Traversal) == 1 && e.Traversal.RootName() == "all" {
+			return []string{"*all*"}
+		}
+		if path, ok := traversalPath(e.Traversal); ok {
+			return []string{path}
 		}
 	case *hclsyntax.TemplateExpr:
 		if len(e.Parts) == 1 {
@@ -107,17 +120,34 @@ func (blockData *BlockData) Validate(
 	schema *SchemaBlock,
 	parentIgnore []string,
 	findings *[]ValidationFinding,
-) {
+) Coverage {
+	var coverage Coverage
 	if schema == nil {
-		return
+		coverage.updatePercent()
+		return coverage
 	}
 
 	ignore := make([]string, len(parentIgnore), len(parentIgnore)+len(blockData.IgnoreChanges))
 	copy(ignore, parentIgnore)
-	ignore = append(ignore, blockData.IgnoreChanges...)
+	for _, entry := range blockData.IgnoreChanges {
+		if entry == "*all*" {
+			ignore = append(ignore, entry)
+			continue
+		}
+		ignore = append(ignore, relativePath(path, entry))
+	}
+
+	blockData.validateAttributes(resourceType, path, schema, ignore, findings, &coverage)
+	blockData.validateBlocks(resourceType, path, schema, ignore, findings, &coverage)
+	blockData.validateNestedTypes(resourceType, path, schema, ignore, findings, &coverage)
+	blockData.validateUnknown(resourceType, path, schema, findings)
+	blockData.validateDeprecated(resourceType, path, schema, findings)
+	blockData.validateTypes(resourceType, path, schema, findings)
+	blockData.validateSensitive(resourceType, path, schema, findings)
+	blockData.validateIgnoreChanges(resourceType, path, schema, findings)
 
-	blockData.validateAttributes(resourceType, path, schema, ignore, findings)
-	blockData.validateBlocks(resourceType, path, schema, ignore, findings)
+	coverage.updatePercent()
+	return coverage
 }
 
 func (blockData *BlockData) validateAttributes(
@@ -125,6 +155,7 @@ func (blockData *BlockData) validateAttributes(
 	schema *SchemaBlock,
 	ignore []string,
 	findings *[]ValidationFinding,
+	coverage *Coverage,
 ) {
 	for name, attribute := range schema.Attributes {
 		if name == "id" {
@@ -139,17 +170,34 @@ func (blockData *BlockData) validateAttributes(
 			continue
 		}
 
-		if isIgnored(ignore, name) {
+		if isIgnored(ignore, path, name) {
 			continue
 		}
 
-		if !blockData.Properties[name] {
+		class, configured := blockData.Properties[name]
+		coverage.add(configured && class != ValueNull)
+
+		if !configured {
 			*findings = append(*findings, ValidationFinding{
 				ResourceType: resourceType,
 				Path:         path,
 				Name:         name,
+				Category:     CategoryMissing,
 				Required:     attribute.Required,
 				IsBlock:      false,
+				Location:     blockData.Range,
+			})
+			continue
+		}
+
+		if class == ValueNull && attribute.Required {
+			*findings = append(*findings, ValidationFinding{
+				ResourceType: resourceType,
+				Path:         path,
+				Name:         name,
+				Category:     CategoryNull,
+				Required:     true,
+				Location:     blockData.Range,
 			})
 		}
 	}
@@ -160,60 +208,611 @@ func (blockData *BlockData) validateBlocks(
 	schema *SchemaBlock,
 	ignore []string,
 	findings *[]ValidationFinding,
+	coverage *Coverage,
 ) {
 	for name, blockType := range schema.BlockTypes {
-		if name == "timeouts" || isIgnored(ignore, name) {
-			continue
-		}
-
-		if blockType.Deprecated {
+		if name == "timeouts" || blockType.IsDeprecated() {
 			continue
 		}
 
 		staticBlocks := blockData.StaticBlocks[name]
 		dynamic := blockData.DynamicBlocks[name]
+		// Attribute syntax for a block, as in ip_restriction = [], configures it without nested blocks.
+		_, asAttribute := blockData.Properties[name]
+		configured := len(staticBlocks) > 0 || dynamic != nil || asAttribute
 
-		if len(staticBlocks) == 0 && dynamic == nil {
-			*findings = append(*findings, ValidationFinding{
-				ResourceType: resourceType,
-				Path:         path,
-				Name:         name,
-				Required:     blockType.MinItems > 0,
-				IsBlock:      true,
-			})
+		// ignore_changes only hides an absent block; a configured one is still checked throughout.
+		ignored := isIgnored(ignore, path, name)
+		if !ignored {
+			coverage.add(configured)
+		}
+
+		if !configured {
+			if !ignored {
+				*findings = append(*findings, ValidationFinding{
+					ResourceType: resourceType,
+					Path:         path,
+					Name:         name,
+					Category:     CategoryMissing,
+					Required:     blockType.MinItems > 0,
+					IsBlock:      true,
+					Location:     blockData.Range,
+				})
+			}
 			continue
 		}
 
+		// The number of dynamic blocks is only known at plan time.
+		if dynamic == nil && !asAttribute {
+			blockData.validateCardinality(resourceType, path, name, blockType, staticBlocks, findings)
+		}
+
 		for i, blk := range staticBlocks {
 			blockPath := fmt.Sprintf("%s.%s", path, name)
 			if len(staticBlocks) > 1 {
 				blockPath = fmt.Sprintf("%s.%s[%d]", path, name, i)
 			}
-			blk.Data.Validate(resourceType, blockPath, blockType.Block, ignore, findings)
+			coverage.merge(blk.Data.Validate(resourceType, blockPath, blockType.Block, ignore, findings))
 		}
 
 		if dynamic != nil {
 			blockPath := fmt.Sprintf("%s.%s", path, name)
-			dynamic.Data.Validate(resourceType, blockPath, blockType.Block, ignore, findings)
+			coverage.merge(dynamic.Data.Validate(resourceType, blockPath, blockType.Block, ignore, findings))
+		}
+	}
+}
+
+func (blockData *BlockData) validateCardinality(
+	resourceType, path, name string,
+	blockType *SchemaBlockType,
+	staticBlocks []*ParsedBlock,
+	findings *[]ValidationFinding,
+) {
+	count := len(staticBlocks)
+
+	if maxBlocks := blockType.MaxBlocks(); maxBlocks > 0 && count > maxBlocks {
+		location := staticBlocks[maxBlocks].Data.Range
+		if location.IsZero() {
+			location = blockData.Range
+		}
+		*findings = append(*findings, ValidationFinding{
+			ResourceType: resourceType,
+			Path:         path,
+			Name:         name,
+			Category:     CategoryCardinality,
+			Required:     blockType.MinItems > 0,
+			IsBlock:      true,
+			Location:     location,
+			Detail:       fmt.Sprintf("declared %d %s, at most %d allowed", count, plural(count, "time", "times"), maxBlocks),
+		})
+	}
+
+	if count < blockType.MinItems {
+		*findings = append(*findings, ValidationFinding{
+			ResourceType: resourceType,
+			Path:         path,
+			Name:         name,
+			Category:     CategoryCardinality,
+			Required:     blockType.MinItems > 0,
+			IsBlock:      true,
+			Location:     blockData.Range,
+			Detail:       fmt.Sprintf("declared %d %s, at least %d required", count, plural(count, "time", "times"), blockType.MinItems),
+		})
+	}
+}
+
+func (blockData *BlockData) validateNestedTypes(
+	resourceType, path string,
+	schema *SchemaBlock,
+	ignore []string,
+	findings *[]ValidationFinding,
+	coverage *Coverage,
+) {
+	for name, attribute := range schema.Attributes {
+		if attribute.NestedType == nil {
+			continue
+		}
+
+		expr, ok := blockData.Expressions[name]
+		if !ok {
+			continue
+		}
+
+		nestedSchema := &SchemaBlock{Attributes: attribute.NestedType.Attributes}
+		attributePath := fmt.Sprintf("%s.%s", path, name)
+
+		switch attribute.NestedType.NestingMode {
+		case "single":
+			if object, ok := parseObjectLiteral(expr); ok {
+				coverage.merge(object.Validate(resourceType, attributePath, nestedSchema, ignore, findings))
+			}
+		case "list", "set":
+			tuple, ok := expr.(*hclsyntax.TupleConsExpr)
+			if !ok {
+				continue
+			}
+			for i, item := range tuple.Exprs {
+				if object, ok := parseObjectLiteral(item); ok {
+					coverage.merge(object.Validate(resourceType, fmt.Sprintf("%s[%d]", attributePath, i), nestedSchema, ignore, findings))
+				}
+			}
+		case "map":
+			objects, ok := expr.(*hclsyntax.ObjectConsExpr)
+			if !ok {
+				continue
+			}
+			for _, item := range objects.Items {
+				key, ok := objectLiteralKey(item.KeyExpr)
+				if !ok {
+					continue
+				}
+				if object, ok := parseObjectLiteral(item.ValueExpr); ok {
+					coverage.merge(object.Validate(resourceType, fmt.Sprintf("%s[%q]", attributePath, key), nestedSchema, ignore, findings))
+				}
+			}
+		}
+	}
+}
+
+func parseObjectLiteral(expr hclsyntax.Expression) (*BlockData, bool) {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, false
+	}
+
+	data := NewBlockData()
+	data.Range = NewSourceRange(object.SrcRange)
+	for _, item := range object.Items {
+		key, ok := objectLiteralKey(item.KeyExpr)
+		if !ok {
+			return nil, false
+		}
+		data.Properties[key] = classifyExpression(item.ValueExpr)
+		data.Expressions[key] = item.ValueExpr
+	}
+	return &data, true
+}
+
+func objectLiteralKey(expr hclsyntax.Expression) (string, bool) {
+	if keyword := hcl.ExprAsKeyword(expr); keyword != "" {
+		return keyword, true
+	}
+
+	value, ok := constantValue(expr)
+	if !ok || value.IsNull() || !value.Type().Equals(cty.String) {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+var metaArguments = map[string]bool{
+	"count":       true,
+	"for_each":    true,
+	"provider":    true,
+	"depends_on":  true,
+	"lifecycle":   true,
+	"provisioner": true,
+	"connection":  true,
+}
+
+func (blockData *BlockData) validateUnknown(
+	resourceType, path string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	for name := range blockData.Properties {
+		if metaArguments[name] {
+			continue
+		}
+
+		if _, isDynamic := blockData.DynamicBlocks[name]; isDynamic {
+			if _, ok := schema.BlockTypes[name]; !ok {
+				blockData.appendUnknown(resourceType, path, name, true, schema, findings)
+			}
+			continue
+		}
+
+		_, isAttribute := schema.Attributes[name]
+		_, isBlock := schema.BlockTypes[name]
+		if !isAttribute && !isBlock {
+			blockData.appendUnknown(resourceType, path, name, false, schema, findings)
+		}
+	}
+
+	for name := range blockData.StaticBlocks {
+		if metaArguments[name] {
+			continue
+		}
+
+		if _, ok := schema.BlockTypes[name]; !ok {
+			blockData.appendUnknown(resourceType, path, name, true, schema, findings)
+		}
+	}
+}
+
+func (blockData *BlockData) appendUnknown(
+	resourceType, path, name string,
+	isBlock bool,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	var candidates []string
+	if isBlock {
+		candidates = slices.Collect(maps.Keys(schema.BlockTypes))
+	} else {
+		candidates = slices.Collect(maps.Keys(schema.Attributes))
+	}
+
+	*findings = append(*findings, ValidationFinding{
+		ResourceType: resourceType,
+		Path:         path,
+		Name:         name,
+		Category:     CategoryUnknown,
+		IsBlock:      isBlock,
+		Location:     blockData.Range,
+		Suggestion:   suggestName(name, candidates),
+	})
+}
+
+func suggestName(name string, candidates []string) string {
+	slices.Sort(candidates)
+
+	best := ""
+	bestDistance := 0
+	for _, candidate := range candidates {
+		distance := levenshtein.Distance(name, candidate, nil)
+		if best == "" || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	if best == "" || bestDistance > 3 || bestDistance > len(name)/2 {
+		return ""
+	}
+	return best
+}
+
+func (blockData *BlockData) validateDeprecated(
+	resourceType, path string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	for name := range blockData.Properties {
+		if _, isDynamic := blockData.DynamicBlocks[name]; isDynamic {
+			blockData.checkDeprecatedBlock(resourceType, path, name, schema, findings)
+			continue
+		}
+		if attribute, ok := schema.Attributes[name]; ok && attribute.Deprecated {
+			blockData.appendDeprecated(resourceType, path, name, false, attribute.Description, findings)
+		}
+	}
+
+	for name := range blockData.StaticBlocks {
+		blockData.checkDeprecatedBlock(resourceType, path, name, schema, findings)
+	}
+}
+
+func (blockData *BlockData) checkDeprecatedBlock(
+	resourceType, path, name string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	blockType, ok := schema.BlockTypes[name]
+	if !ok || !blockType.IsDeprecated() {
+		return
+	}
+
+	description := ""
+	if blockType.Block != nil {
+		description = blockType.Block.Description
+	}
+	blockData.appendDeprecated(resourceType, path, name, true, description, findings)
+}
+
+func (blockData *BlockData) appendDeprecated(
+	resourceType, path, name string,
+	isBlock bool,
+	description string,
+	findings *[]ValidationFinding,
+) {
+	*findings = append(*findings, ValidationFinding{
+		ResourceType: resourceType,
+		Path:         path,
+		Name:         name,
+		Category:     CategoryDeprecated,
+		IsBlock:      isBlock,
+		Location:     blockData.Range,
+		Detail:       strings.TrimSpace(description),
+	})
+}
+
+func (blockData *BlockData) validateTypes(
+	resourceType, path string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	for name, expr := range blockData.Expressions {
+		attribute, ok := schema.Attributes[name]
+		if !ok || attribute.Type == cty.NilType {
+			continue
+		}
+
+		value, ok := constantValue(expr)
+		if !ok || value.IsNull() {
+			continue
+		}
+
+		if _, err := convert.Convert(value, attribute.Type); err != nil {
+			*findings = append(*findings, ValidationFinding{
+				ResourceType: resourceType,
+				Path:         path,
+				Name:         name,
+				Category:     CategoryType,
+				Required:     attribute.Required,
+				Location:     NewSourceRange(expr.Range()),
+				Detail:       fmt.Sprintf("%s required, got %s", attribute.Type.FriendlyName(), value.Type().FriendlyName()),
+			})
+		}
+	}
+}
+
+func (blockData *BlockData) validateSensitive(
+	resourceType, path string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	for name, expr := range blockData.Expressions {
+		attribute, ok := schema.Attributes[name]
+		if !ok || !attribute.Sensitive {
+			continue
+		}
+
+		value, ok := constantValue(expr)
+		if !ok || value.IsNull() || !value.Type().Equals(cty.String) || value.AsString() == "" {
+			continue
+		}
+
+		*findings = append(*findings, ValidationFinding{
+			ResourceType: resourceType,
+			Path:         path,
+			Name:         name,
+			Category:     CategorySensitive,
+			Required:     attribute.Required,
+			Location:     NewSourceRange(expr.Range()),
+		})
+	}
+}
+
+func (blockData *BlockData) validateIgnoreChanges(
+	resourceType, path string,
+	schema *SchemaBlock,
+	findings *[]ValidationFinding,
+) {
+	for _, entry := range blockData.IgnoreChanges {
+		if entry == "*all*" {
+			continue
+		}
+
+		detail := ""
+		attribute, ok := resolveIgnoreChanges(entry, schema)
+		switch {
+		case !ok:
+			detail = "does not match any attribute or block in the schema"
+		case attribute != nil && attribute.Computed && !attribute.Optional && !attribute.Required:
+			detail = "refers to a computed-only attribute, so ignoring it has no effect"
+		default:
+			continue
+		}
+
+		*findings = append(*findings, ValidationFinding{
+			ResourceType: resourceType,
+			Path:         path,
+			Name:         entry,
+			Category:     CategoryIgnoreChanges,
+			Location:     blockData.Range,
+			Detail:       detail,
+		})
+	}
+}
+
+func resolveIgnoreChanges(entry string, schema *SchemaBlock) (*SchemaAttribute, bool) {
+	steps, diags := hclsyntax.ParseTraversalAbs([]byte(entry), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		steps = hcl.Traversal{hcl.TraverseRoot{Name: entry}}
+	}
+
+	attributes, blockTypes := schema.Attributes, schema.BlockTypes
+	var resolved *SchemaAttribute
+	for _, step := range steps {
+		name := traverserName(step)
+		if name == "" {
+			continue
+		}
+
+		if attribute, ok := attributes[name]; ok {
+			if attribute.NestedType == nil {
+				return attribute, true
+			}
+			resolved = attribute
+			attributes, blockTypes = attribute.NestedType.Attributes, nil
+			continue
+		}
+
+		blockType, ok := blockTypes[name]
+		if !ok || blockType.Block == nil {
+			return nil, false
+		}
+		resolved = nil
+		attributes, blockTypes = blockType.Block.Attributes, blockType.Block.BlockTypes
+	}
+	return resolved, true
+}
+
+func constantValue(expr hclsyntax.Expression) (cty.Value, bool) {
+	if len(expr.Variables()) > 0 {
+		return cty.NilVal, false
+	}
+
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+var inputRoots = map[string]bool{
+	"var":   true,
+	"local": true,
+	"each":  true,
+}
+
+func classifyExpression(expr hclsyntax.Expression) ValueClass {
+	if value, ok := constantValue(expr); ok {
+		if value.IsNull() {
+			return ValueNull
+		}
+		return ValueConstant
+	}
+
+	if wrap, ok := expr.(*hclsyntax.TemplateWrapExpr); ok {
+		expr = wrap.Wrapped
+	}
+	if traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr); ok && inputRoots[traversal.Traversal.RootName()] {
+		return ValueReference
+	}
+	return ValueComplex
+}
+
+func (blockData *BlockData) CountValues() ValueCounts {
+	var counts ValueCounts
+
+	for name, class := range blockData.Properties {
+		if _, isDynamic := blockData.DynamicBlocks[name]; isDynamic || metaArguments[name] {
+			continue
+		}
+		counts.add(class)
+	}
+
+	for _, blocks := range blockData.StaticBlocks {
+		for _, block := range blocks {
+			counts.merge(block.Data.CountValues())
 		}
 	}
+	for _, block := range blockData.DynamicBlocks {
+		counts.merge(block.Data.CountValues())
+	}
+
+	return counts
 }
 
-func isIgnored(ignore []string, name string) bool {
+func isIgnored(ignore []string, path, name string) bool {
+	target := relativePath(path, name)
 	for _, item := range ignore {
 		if item == "*all*" {
 			return true
 		}
-		if strings.EqualFold(item, name) {
+		if ignoreChangesMatch(item, target) {
 			return true
 		}
 	}
 	return false
 }
 
+func relativePath(path, name string) string {
+	parent := strings.TrimPrefix(strings.TrimPrefix(path, "root"), ".")
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func traversalPath(traversal hcl.Traversal) (string, bool) {
+	var b strings.Builder
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			b.WriteString(s.Name)
+		case hcl.TraverseAttr:
+			b.WriteString("." + s.Name)
+		case hcl.TraverseIndex:
+			switch {
+			case s.Key.Type() == cty.Number && s.Key.IsKnown() && !s.Key.IsNull():
+				b.WriteString("[" + s.Key.AsBigFloat().Text('f', -1) + "]")
+			case s.Key.Type() == cty.String && s.Key.IsKnown() && !s.Key.IsNull():
+				fmt.Fprintf(&b, "[%q]", s.Key.AsString())
+			default:
+				return "", false
+			}
+		default:
+			return "", false
+		}
+	}
+	return b.String(), b.Len() > 0
+}
+
+// An ignore_changes entry without an index covers every element of a block list, and an index
+// in the entry matches a target without one, since single and dynamic blocks have no index.
+func ignoreChangesMatch(entry, target string) bool {
+	entrySteps, diags := hclsyntax.ParseTraversalAbs([]byte(entry), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return strings.EqualFold(entry, target)
+	}
+	targetSteps, diags := hclsyntax.ParseTraversalAbs([]byte(target), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return strings.EqualFold(entry, target)
+	}
+
+	j := 0
+	for i := 0; i < len(entrySteps); {
+		if j >= len(targetSteps) {
+			return false
+		}
+
+		entryIndex, entryIsIndex := entrySteps[i].(hcl.TraverseIndex)
+		targetIndex, targetIsIndex := targetSteps[j].(hcl.TraverseIndex)
+
+		switch {
+		case entryIsIndex && targetIsIndex:
+			if !entryIndex.Key.Equals(targetIndex.Key).True() {
+				return false
+			}
+			i++
+			j++
+		case entryIsIndex:
+			i++
+		case targetIsIndex:
+			j++
+		default:
+			if !strings.EqualFold(traverserName(entrySteps[i]), traverserName(targetSteps[j])) {
+				return false
+			}
+			i++
+			j++
+		}
+	}
+	return true
+}
+
+func traverserName(step hcl.Traverser) string {
+	switch s := step.(type) {
+	case hcl.TraverseRoot:
+		return s.Name
+	case hcl.TraverseAttr:
+		return s.Name
+	}
+	return ""
+}
+
 func mergeBlocks(dest, src *ParsedBlock) {
-	for key := range src.Data.Properties {
-		dest.Data.Properties[key] = true
+	for key, class := range src.Data.Properties {
+		dest.Data.Properties[key] = class
+	}
+
+	for key, expr := range src.Data.Expressions {
+		if dest.Data.Expressions == nil {
+			dest.Data.Expressions = make(map[string]hclsyntax.Expression)
+		}
+		dest.Data.Expressions[key] = expr
 	}
 
 	for key, blocks := range src.Data.StaticBlocks {
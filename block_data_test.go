@@ -7,6 +7,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestNewBlockDataInitializesCollections(t *testing.T) {
@@ -53,10 +54,10 @@ subnet {
 	bd.ParseAttributes(body)
 	bd.ParseBlocks(body)
 
-	if diff := cmp.Diff(map[string]bool{
-		"name":          true,
-		"address_space": true,
-		"subnet":        true,
+	if diff := cmp.Diff(map[string]ValueClass{
+		"name":          ValueConstant,
+		"address_space": ValueConstant,
+		"subnet":        ValueComplex,
 	}, bd.Properties); diff != "" {
 		t.Fatalf("Properties mismatch (-want +got):\n%s", diff)
 	}
@@ -66,9 +67,9 @@ subnet {
 	}
 
 	staticSubnet := bd.StaticBlocks["subnet"][0]
-	if diff := cmp.Diff(map[string]bool{
-		"name":           true,
-		"address_prefix": true,
+	if diff := cmp.Diff(map[string]ValueClass{
+		"name":           ValueConstant,
+		"address_prefix": ValueConstant,
 	}, staticSubnet.Data.Properties); diff != "" {
 		t.Fatalf("Static subnet properties mismatch (-want +got):\n%s", diff)
 	}
@@ -78,14 +79,14 @@ subnet {
 		t.Fatalf("Dynamic subnet block should be parsed")
 	}
 
-	if diff := cmp.Diff(map[string]bool{
-		"name":           true,
-		"address_prefix": true,
+	if diff := cmp.Diff(map[string]ValueClass{
+		"name":           ValueReference,
+		"address_prefix": ValueReference,
 	}, dynamicSubnet.Data.Properties); diff != "" {
 		t.Fatalf("Dynamic subnet properties mismatch (-want +got):\n%s", diff)
 	}
 
-	if diff := cmp.Diff([]string{"tags"}, bd.IgnoreChanges, cmpopts.SortSlices(func(a, b string) bool {
+	if diff := cmp.Diff([]string{"location", "tags"}, bd.IgnoreChanges, cmpopts.SortSlices(func(a, b string) bool {
 		return a < b
 	})); diff != "" {
 		t.Fatalf("IgnoreChanges mismatch (-want +got):\n%s", diff)
@@ -113,7 +114,7 @@ func TestBlockDataValidateReportsMissingParts(t *testing.T) {
 	}
 
 	bd := BlockData{
-		Properties:    map[string]bool{"location": true}, // missing "name"
+		Properties:    map[string]ValueClass{"location": ValueConstant}, // missing "name"
 		StaticBlocks:  map[string][]*ParsedBlock{},
 		DynamicBlocks: map[string]*ParsedBlock{},
 		IgnoreChanges: []string{"tags"},
@@ -127,6 +128,7 @@ func TestBlockDataValidateReportsMissingParts(t *testing.T) {
 			ResourceType: "azurerm_virtual_network",
 			Path:         "azurerm_virtual_network.test",
 			Name:         "name",
+			Category:     CategoryMissing,
 			Required:     true,
 			IsBlock:      false,
 		},
@@ -134,6 +136,7 @@ func TestBlockDataValidateReportsMissingParts(t *testing.T) {
 			ResourceType: "azurerm_virtual_network",
 			Path:         "azurerm_virtual_network.test",
 			Name:         "subnet",
+			Category:     CategoryMissing,
 			Required:     true,
 			IsBlock:      true,
 		},
@@ -151,14 +154,787 @@ func TestBlockDataValidateReportsMissingParts(t *testing.T) {
 	}
 }
 
+func TestParseBlocksRecordsSourceRanges(t *testing.T) {
+	body := parseHCLBody(t, `
+site_config {
+  always_on = true
+}
+
+dynamic "identity" {
+  for_each = var.identity
+  content {
+    type = identity.value.type
+  }
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseBlocks(body)
+
+	static := bd.StaticBlocks["site_config"][0].Data.Range
+	if static.Filename != "test.hcl" || static.Start.Line != 2 || static.End.Line != 4 {
+		t.Fatalf("unexpected static block range: %+v", static)
+	}
+
+	dynamic := bd.DynamicBlocks["identity"].Data.Range
+	if dynamic.Start.Line != 6 || dynamic.End.Line != 11 {
+		t.Fatalf("dynamic block range should cover the dynamic block, got %+v", dynamic)
+	}
+}
+
+func TestBlockDataValidateReportsEnclosingBlockLocation(t *testing.T) {
+	body := parseHCLBody(t, `
+site_config {
+  always_on = true
+}
+`)
+
+	bd := NewBlockData()
+	bd.Range = SourceRange{Filename: "test.hcl", Start: SourcePos{Line: 1, Column: 1}, End: SourcePos{Line: 5, Column: 2}}
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"name": {Required: true},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on":        {Optional: true},
+						"app_command_line": {Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, nil, &findings)
+
+	locations := map[string]SourceRange{}
+	for _, f := range findings {
+		locations[f.Name] = f.Location
+	}
+
+	if locations["name"] != bd.Range {
+		t.Fatalf("missing top-level attribute should point at the resource, got %+v", locations["name"])
+	}
+	if got := locations["app_command_line"]; got.Start.Line != 2 || got.End.Line != 4 {
+		t.Fatalf("missing nested attribute should point at its enclosing block, got %+v", got)
+	}
+}
+
+func TestBlockDataValidateReportsUnknownParts(t *testing.T) {
+	body := parseHCLBody(t, `
+count                        = 1
+pubic_network_access_enabled = true
+
+site_config {
+  always_onn = true
+}
+
+dynamic "identity" {
+  for_each = var.identities
+  content {
+    type = identity.value
+  }
+}
+
+sight_config {}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"public_network_access_enabled": {Optional: true},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on": {Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, nil, &findings)
+
+	unknown := map[string]ValidationFinding{}
+	for _, f := range findings {
+		if f.Category == CategoryUnknown {
+			unknown[f.Path+"."+f.Name] = f
+		}
+	}
+
+	want := map[string]struct {
+		isBlock    bool
+		suggestion string
+	}{
+		"root.pubic_network_access_enabled": {suggestion: "public_network_access_enabled"},
+		"root.site_config.always_onn":       {suggestion: "always_on"},
+		"root.identity":                     {isBlock: true},
+		"root.sight_config":                 {isBlock: true, suggestion: "site_config"},
+	}
+	if len(unknown) != len(want) {
+		t.Fatalf("expected %d unknown findings, got %+v", len(want), unknown)
+	}
+	for key, w := range want {
+		got, ok := unknown[key]
+		if !ok {
+			t.Fatalf("expected unknown finding for %s, got %+v", key, unknown)
+		}
+		if got.IsBlock != w.isBlock || got.Suggestion != w.suggestion {
+			t.Fatalf("unexpected finding for %s: %+v", key, got)
+		}
+	}
+}
+
+func TestBlockDataValidateReportsDeprecatedInUse(t *testing.T) {
+	body := parseHCLBody(t, `
+enable_https_traffic_only = true
+
+network_rules {
+  default_action = "Deny"
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"enable_https_traffic_only": {Optional: true, Deprecated: true, Description: "Use https_traffic_only_enabled instead. "},
+			"allow_blob_public_access":  {Optional: true, Deprecated: true},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"network_rules": {
+				Block: &SchemaBlock{
+					Deprecated:  true,
+					Description: "Use azurerm_storage_account_network_rules instead.",
+					Attributes: map[string]*SchemaAttribute{
+						"default_action": {Required: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_storage_account", "root", schema, nil, &findings)
+
+	want := []ValidationFinding{
+		{ResourceType: "azurerm_storage_account", Path: "root", Name: "enable_https_traffic_only", Category: CategoryDeprecated, Detail: "Use https_traffic_only_enabled instead."},
+		{ResourceType: "azurerm_storage_account", Path: "root", Name: "network_rules", Category: CategoryDeprecated, IsBlock: true, Detail: "Use azurerm_storage_account_network_rules instead."},
+	}
+	if diff := cmp.Diff(want, findings); diff != "" {
+		t.Fatalf("findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateReportsBlockCardinality(t *testing.T) {
+	body := parseHCLBody(t, `
+identity {
+  type = "SystemAssigned"
+}
+
+identity {
+  type = "UserAssigned"
+}
+
+site_config {}
+site_config {}
+
+ip_restriction {}
+
+dynamic "storage_account" {
+  for_each = var.storage_accounts
+  content {}
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"identity":        {Nesting: "list", MaxItems: 1, Block: &SchemaBlock{Attributes: map[string]*SchemaAttribute{"type": {Required: true}}}},
+			"site_config":     {Nesting: "single", Block: &SchemaBlock{}},
+			"ip_restriction":  {Nesting: "list", MinItems: 2, Block: &SchemaBlock{}},
+			"storage_account": {Nesting: "set", MaxItems: 1, Block: &SchemaBlock{}},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, nil, &findings)
+
+	details := map[string]string{}
+	for _, f := range findings {
+		if f.Category == CategoryCardinality {
+			details[f.Name] = f.Detail
+		}
+	}
+
+	want := map[string]string{
+		"identity":       "declared 2 times, at most 1 allowed",
+		"site_config":    "declared 2 times, at most 1 allowed",
+		"ip_restriction": "declared 1 time, at least 2 required",
+	}
+	if diff := cmp.Diff(want, details); diff != "" {
+		t.Fatalf("cardinality findings mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, f := range findings {
+		if f.Name == "identity" && f.Category == CategoryCardinality && f.Location.Start.Line != 6 {
+			t.Fatalf("excess block should point at the first extra declaration, got %+v", f.Location)
+		}
+		if f.Category == CategoryCardinality && (f.Required != (f.Name == "ip_restriction") || !isErrorFinding(f)) {
+			t.Fatalf("cardinality findings should be errors that only mark blocks with min_items required, got %+v", f)
+		}
+	}
+}
+
+func TestBlockDataValidateReportsCardinalityOfIgnoredBlocks(t *testing.T) {
+	body := parseHCLBody(t, `
+identity {
+  type = "SystemAssigned"
+}
+
+identity {
+  type = "UserAssigned"
+}
+
+site_config {}
+site_config {}
+
+lifecycle {
+  ignore_changes = [identity, site_config]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"identity":    {Nesting: "list", MaxItems: 1, Block: &SchemaBlock{Attributes: map[string]*SchemaAttribute{"type": {Required: true}}}},
+			"site_config": {Nesting: "single", Block: &SchemaBlock{}},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, bd.IgnoreChanges, &findings)
+
+	details := map[string]string{}
+	for _, f := range findings {
+		if f.Category == CategoryCardinality {
+			details[f.Name] = f.Detail
+		}
+	}
+
+	want := map[string]string{
+		"identity":    "declared 2 times, at most 1 allowed",
+		"site_config": "declared 2 times, at most 1 allowed",
+	}
+	if diff := cmp.Diff(want, details); diff != "" {
+		t.Fatalf("cardinality findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateReportsTypeMismatches(t *testing.T) {
+	body := parseHCLBody(t, `
+https_only          = "yes"
+client_cert_enabled = "true"
+app_settings        = { WEBSITE_RUN_FROM_PACKAGE = "1" }
+tags                = var.tags
+location            = azurerm_resource_group.rg.location
+name                = lower("APP")
+ports               = [80, 443]
+zones               = [{ zone = 1 }]
+
+site_config {
+  worker_count = "two"
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"https_only":          {Type: cty.Bool, Optional: true},
+			"client_cert_enabled": {Type: cty.Bool, Optional: true},
+			"app_settings":        {Type: cty.Map(cty.String), Optional: true},
+			"tags":                {Type: cty.Map(cty.String), Optional: true},
+			"location":            {Type: cty.String, Required: true},
+			"name":                {Type: cty.Number, Required: true},
+			"ports":               {Type: cty.Set(cty.String), Optional: true},
+			"zones":               {Type: cty.List(cty.String), Optional: true},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"worker_count": {Type: cty.Number, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, nil, &findings)
+
+	details := map[string]string{}
+	for _, f := range findings {
+		if f.Category == CategoryType {
+			details[f.Path+"."+f.Name] = f.Detail
+		}
+	}
+
+	want := map[string]string{
+		"root.https_only":               "bool required, got string",
+		"root.zones":                    "list of string required, got tuple",
+		"root.site_config.worker_count": "number required, got string",
+	}
+	if diff := cmp.Diff(want, details); diff != "" {
+		t.Fatalf("type findings mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, f := range findings {
+		if f.Category == CategoryType && (f.Required || !isErrorFinding(f)) {
+			t.Fatalf("type findings on optional attributes should be errors without Required, got %+v", f)
+		}
+	}
+}
+
+func TestBlockDataValidatesNestedTypeAttributes(t *testing.T) {
+	body := parseHCLBody(t, `
+identity = {
+  type = "SystemAssigned"
+}
+
+ip_rules = [
+  { action = "Allow" },
+  { action = "Deny", ip_range = "10.0.0.0/8", priorty = 100 },
+]
+
+endpoints = {
+  "primary" = { port = "http" }
+}
+
+secondary = var.secondary
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"identity": {Optional: true, NestedType: &SchemaNestedType{
+				NestingMode: "single",
+				Attributes: map[string]*SchemaAttribute{
+					"type":         {Type: cty.String, Required: true},
+					"identity_ids": {Type: cty.Set(cty.String), Optional: true},
+				},
+			}},
+			"ip_rules": {Optional: true, NestedType: &SchemaNestedType{
+				NestingMode: "list",
+				Attributes: map[string]*SchemaAttribute{
+					"action":   {Type: cty.String, Required: true},
+					"ip_range": {Type: cty.String, Required: true},
+					"priority": {Type: cty.Number, Optional: true},
+				},
+			}},
+			"endpoints": {Optional: true, NestedType: &SchemaNestedType{
+				NestingMode: "map",
+				Attributes: map[string]*SchemaAttribute{
+					"port": {Type: cty.Number, Required: true},
+				},
+			}},
+			"secondary": {Optional: true, NestedType: &SchemaNestedType{
+				NestingMode: "single",
+				Attributes: map[string]*SchemaAttribute{
+					"enabled": {Type: cty.Bool, Required: true},
+				},
+			}},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azapi_resource", "root", schema, nil, &findings)
+
+	got := map[string]FindingCategory{}
+	for _, f := range findings {
+		got[f.Path+"."+f.Name] = f.Category
+	}
+
+	want := map[string]FindingCategory{
+		"root.identity.identity_ids":     CategoryMissing,
+		"root.ip_rules[0].ip_range":      CategoryMissing,
+		"root.ip_rules[0].priority":      CategoryMissing,
+		"root.ip_rules[1].priority":      CategoryMissing,
+		"root.ip_rules[1].priorty":       CategoryUnknown,
+		`root.endpoints["primary"].port`: CategoryType,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("nested type findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateReportsHardcodedSensitiveValues(t *testing.T) {
+	body := parseHCLBody(t, `
+administrator_login_password = "P@ssw0rd!"
+client_secret                = var.client_secret
+access_key                   = data.azurerm_key_vault_secret.key.value
+connection_string            = "Server=${var.server};Password=secret"
+primary_key                  = ""
+name                         = "sql"
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"administrator_login_password": {Type: cty.String, Optional: true, Sensitive: true},
+			"client_secret":                {Type: cty.String, Optional: true, Sensitive: true},
+			"access_key":                   {Type: cty.String, Optional: true, Sensitive: true},
+			"connection_string":            {Type: cty.String, Optional: true, Sensitive: true},
+			"primary_key":                  {Type: cty.String, Optional: true, Sensitive: true},
+			"name":                         {Type: cty.String, Required: true},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_mssql_server", "root", schema, nil, &findings)
+
+	var sensitive []string
+	for _, f := range findings {
+		if f.Category == CategorySensitive {
+			sensitive = append(sensitive, f.Name)
+			if f.Location.Start.Line != 2 {
+				t.Fatalf("finding should point at the assigned value, got %+v", f.Location)
+			}
+		}
+	}
+
+	if diff := cmp.Diff([]string{"administrator_login_password"}, sensitive); diff != "" {
+		t.Fatalf("sensitive findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseIgnoreChangesPaths(t *testing.T) {
+	body := parseHCLBody(t, `
+lifecycle {
+  ignore_changes = [
+    site_config[0].health_check_path,
+    tags["environment"],
+    "app_settings[\"WEBSITE_RUN_FROM_PACKAGE\"]",
+    "identity.0.type",
+    "site_config.0.ip_restriction.1.name",
+  ]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseBlocks(body)
+
+	want := []string{
+		`site_config[0].health_check_path`,
+		`tags["environment"]`,
+		`app_settings["WEBSITE_RUN_FROM_PACKAGE"]`,
+		`identity[0].type`,
+		`site_config[0].ip_restriction[1].name`,
+	}
+	if diff := cmp.Diff(want, bd.IgnoreChanges); diff != "" {
+		t.Fatalf("IgnoreChanges mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIgnoreChangesMatch(t *testing.T) {
+	tests := []struct {
+		entry  string
+		target string
+		want   bool
+	}{
+		{entry: "site_config", target: "site_config.health_check_path", want: true},
+		{entry: "site_config[0].health_check_path", target: "site_config.health_check_path", want: true},
+		{entry: "site_config[0].health_check_path", target: "site_config[0].health_check_path", want: true},
+		{entry: "site_config[0].health_check_path", target: "site_config[1].health_check_path", want: false},
+		{entry: "site_config[0].health_check_path", target: "site_config.always_on", want: false},
+		{entry: "site_config.health_check_path", target: "site_config[1].health_check_path", want: true},
+		{entry: `endpoints["primary"]`, target: `endpoints["primary"].port`, want: true},
+		{entry: `endpoints["primary"]`, target: `endpoints["secondary"].port`, want: false},
+		{entry: "Tags", target: "tags", want: true},
+		{entry: "tags", target: "site_config.tags", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry+"~"+tt.target, func(t *testing.T) {
+			if got := ignoreChangesMatch(tt.entry, tt.target); got != tt.want {
+				t.Errorf("ignoreChangesMatch(%q, %q) = %v, want %v", tt.entry, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockDataValidateHonoursNestedIgnoreChanges(t *testing.T) {
+	body := parseHCLBody(t, `
+site_config {
+  always_on = true
+}
+
+lifecycle {
+  ignore_changes = [site_config[0].health_check_path]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on":         {Optional: true},
+						"health_check_path": {Optional: true},
+						"app_command_line":  {Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, bd.IgnoreChanges, &findings)
+
+	var names []string
+	for _, f := range findings {
+		names = append(names, findingPath(f))
+	}
+	if diff := cmp.Diff([]string{"site_config.app_command_line"}, names); diff != "" {
+		t.Fatalf("findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateHonoursLegacyIndexStrings(t *testing.T) {
+	body := parseHCLBody(t, `
+site_config {
+  health_check_path = "/health"
+}
+
+lifecycle {
+  ignore_changes = ["site_config.0.always_on"]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on":         {Optional: true},
+						"health_check_path": {Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, bd.IgnoreChanges, &findings)
+
+	if len(findings) != 0 {
+		t.Fatalf("legacy index entry should ignore always_on, got %+v", findings)
+	}
+}
+
+func TestBlockDataValidateReportsStaleIgnoreChanges(t *testing.T) {
+	body := parseHCLBody(t, `
+lifecycle {
+  ignore_changes = [
+    tags["environment"],
+    site_config[0].health_check_path,
+    site_config[0].scm_type,
+    identity.type,
+    enable_https_traffic_only,
+    outbound_ip_addresses,
+    all,
+  ]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"tags":                  {Type: cty.Map(cty.String), Optional: true},
+			"outbound_ip_addresses": {Type: cty.String, Computed: true},
+			"identity": {Optional: true, NestedType: &SchemaNestedType{
+				NestingMode: "single",
+				Attributes:  map[string]*SchemaAttribute{"type": {Type: cty.String, Required: true}},
+			}},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"health_check_path": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.validateIgnoreChanges("azurerm_linux_web_app", "root", schema, &findings)
+
+	details := map[string]string{}
+	for _, f := range findings {
+		details[f.Name] = f.Detail
+	}
+
+	want := map[string]string{
+		"site_config[0].scm_type":   "does not match any attribute or block in the schema",
+		"enable_https_traffic_only": "does not match any attribute or block in the schema",
+		"outbound_ip_addresses":     "refers to a computed-only attribute, so ignoring it has no effect",
+	}
+	if diff := cmp.Diff(want, details); diff != "" {
+		t.Fatalf("stale ignore_changes findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateResolvesLegacyIndexIgnoreChanges(t *testing.T) {
+	body := parseHCLBody(t, `
+lifecycle {
+  ignore_changes = ["site_config.0.always_on", "site_config.0.scm_type"]
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on": {Type: cty.Bool, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.validateIgnoreChanges("azurerm_linux_web_app", "root", schema, &findings)
+
+	var names []string
+	for _, f := range findings {
+		names = append(names, f.Name)
+	}
+	if diff := cmp.Diff([]string{"site_config[0].scm_type"}, names); diff != "" {
+		t.Fatalf("stale ignore_changes findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateAcceptsAttributeSyntaxForBlocks(t *testing.T) {
+	body := parseHCLBody(t, `
+ip_restriction = []
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		BlockTypes: map[string]*SchemaBlockType{
+			"ip_restriction": {Nesting: "list", Block: &SchemaBlock{}},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_linux_web_app", "root", schema, nil, &findings)
+
+	if len(findings) != 0 {
+		t.Fatalf("attribute syntax for a block should be accepted, got %+v", findings)
+	}
+}
+
+func TestBlockDataValidateRejectsBlockSyntaxForAttributes(t *testing.T) {
+	body := parseHCLBody(t, `
+tags {
+  environment = "dev"
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"tags": {Optional: true},
+		},
+	}
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_resource_group", "root", schema, nil, &findings)
+
+	var unknown []ValidationFinding
+	for _, f := range findings {
+		if f.Category == CategoryUnknown {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) != 1 || unknown[0].Name != "tags" || !unknown[0].IsBlock {
+		t.Fatalf("block syntax for an attribute should be reported as an unknown block, got %+v", findings)
+	}
+}
+
+func TestSuggestNameRejectsDistantCandidates(t *testing.T) {
+	if got := suggestName("tags", []string{"location", "name"}); got != "" {
+		t.Fatalf("expected no suggestion, got %q", got)
+	}
+	if got := suggestName("locaton", []string{"name", "location"}); got != "location" {
+		t.Fatalf("expected location, got %q", got)
+	}
+}
+
 func TestMergeBlocksCombinesData(t *testing.T) {
 	dest := &ParsedBlock{
 		Data: BlockData{
-			Properties: map[string]bool{"name": true},
+			Properties: map[string]ValueClass{"name": ValueConstant},
 			StaticBlocks: map[string][]*ParsedBlock{
 				"tags": {{
 					Data: BlockData{
-						Properties: map[string]bool{"environment": true},
+						Properties: map[string]ValueClass{"environment": ValueConstant},
 						StaticBlocks: map[string][]*ParsedBlock{
 							"metadata": {},
 						},
@@ -170,7 +946,7 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 			DynamicBlocks: map[string]*ParsedBlock{
 				"rule": {
 					Data: BlockData{
-						Properties:    map[string]bool{"priority": true},
+						Properties:    map[string]ValueClass{"priority": ValueConstant},
 						StaticBlocks:  map[string][]*ParsedBlock{},
 						DynamicBlocks: map[string]*ParsedBlock{},
 						IgnoreChanges: []string{},
@@ -183,11 +959,11 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 
 	src := &ParsedBlock{
 		Data: BlockData{
-			Properties: map[string]bool{"location": true},
+			Properties: map[string]ValueClass{"location": ValueConstant},
 			StaticBlocks: map[string][]*ParsedBlock{
 				"tags": {{
 					Data: BlockData{
-						Properties:    map[string]bool{"costcenter": true},
+						Properties:    map[string]ValueClass{"costcenter": ValueConstant},
 						StaticBlocks:  map[string][]*ParsedBlock{},
 						DynamicBlocks: map[string]*ParsedBlock{},
 						IgnoreChanges: []string{},
@@ -197,7 +973,7 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 			DynamicBlocks: map[string]*ParsedBlock{
 				"rule": {
 					Data: BlockData{
-						Properties:    map[string]bool{"action": true},
+						Properties:    map[string]ValueClass{"action": ValueConstant},
 						StaticBlocks:  map[string][]*ParsedBlock{},
 						DynamicBlocks: map[string]*ParsedBlock{},
 						IgnoreChanges: []string{},
@@ -210,7 +986,7 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 
 	mergeBlocks(dest, src)
 
-	if diff := cmp.Diff(map[string]bool{"name": true, "location": true}, dest.Data.Properties); diff != "" {
+	if diff := cmp.Diff(map[string]ValueClass{"name": ValueConstant, "location": ValueConstant}, dest.Data.Properties); diff != "" {
 		t.Fatalf("Merged properties mismatch (-want +got):\n%s", diff)
 	}
 
@@ -218,7 +994,7 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 		t.Fatalf("Expected 2 tag blocks after merge, got %d", len(dest.Data.StaticBlocks["tags"]))
 	}
 
-	if diff := cmp.Diff(map[string]bool{"priority": true, "action": true}, dest.Data.DynamicBlocks["rule"].Data.Properties); diff != "" {
+	if diff := cmp.Diff(map[string]ValueClass{"priority": ValueConstant, "action": ValueConstant}, dest.Data.DynamicBlocks["rule"].Data.Properties); diff != "" {
 		t.Fatalf("Merged dynamic block properties mismatch (-want +got):\n%s", diff)
 	}
 
@@ -229,6 +1005,156 @@ func TestMergeBlocksCombinesData(t *testing.T) {
 	}
 }
 
+func TestClassifyExpression(t *testing.T) {
+	body := parseHCLBody(t, `
+unset      = null
+name       = "vnet"
+count_val  = 3
+input      = var.instance.name
+wrapped    = "${each.value.name}"
+local_ref  = local.location
+templated  = "${var.prefix}-vnet"
+fallback   = try(var.instance.name, null)
+resource   = azurerm_resource_group.rg.name
+`)
+
+	want := map[string]ValueClass{
+		"unset":     ValueNull,
+		"name":      ValueConstant,
+		"count_val": ValueConstant,
+		"input":     ValueReference,
+		"wrapped":   ValueReference,
+		"local_ref": ValueReference,
+		"templated": ValueComplex,
+		"fallback":  ValueComplex,
+		"resource":  ValueComplex,
+	}
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	if diff := cmp.Diff(want, bd.Properties); diff != "" {
+		t.Fatalf("classification mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateReportsRequiredNullProperties(t *testing.T) {
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"name":     {Required: true},
+			"location": {Required: true},
+			"tags":     {Optional: true},
+		},
+	}
+
+	bd := NewBlockData()
+	bd.ParseAttributes(parseHCLBody(t, `
+name     = null
+location = var.location
+tags     = null
+`))
+
+	var findings []ValidationFinding
+	bd.Validate("azurerm_resource_group", "root", schema, nil, &findings)
+
+	want := []ValidationFinding{
+		{
+			ResourceType: "azurerm_resource_group",
+			Path:         "root",
+			Name:         "name",
+			Category:     CategoryNull,
+			Required:     true,
+		},
+	}
+	if diff := cmp.Diff(want, findings); diff != "" {
+		t.Fatalf("findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataCountValues(t *testing.T) {
+	body := parseHCLBody(t, `
+count    = var.enabled ? 1 : 0
+name     = var.name
+location = "westeurope"
+tags     = null
+
+site_config {
+  always_on = true
+}
+
+dynamic "app_settings" {
+  for_each = var.settings
+  content {
+    name  = app_settings.key
+    value = each.value
+  }
+}
+`)
+
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	want := ValueCounts{Null: 1, Constant: 2, Reference: 2, Complex: 1}
+	if diff := cmp.Diff(want, bd.CountValues()); diff != "" {
+		t.Fatalf("value counts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlockDataValidateReturnsCoverage(t *testing.T) {
+	schema := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{
+			"id":           {Computed: true, Optional: true},
+			"name":         {Required: true},
+			"location":     {Required: true},
+			"tags":         {Optional: true},
+			"https_only":   {Optional: true},
+			"outbound_ips": {Computed: true},
+			"legacy":       {Optional: true, Deprecated: true},
+			"ignored":      {Optional: true},
+		},
+		BlockTypes: map[string]*SchemaBlockType{
+			"site_config": {
+				Nesting: "list",
+				Block: &SchemaBlock{
+					Attributes: map[string]*SchemaAttribute{
+						"always_on":  {Optional: true},
+						"ftps_state": {Optional: true},
+					},
+				},
+			},
+			"identity": {Nesting: "list", Block: &SchemaBlock{}},
+			"timeouts": {Nesting: "single", Block: &SchemaBlock{}},
+		},
+	}
+
+	body := parseHCLBody(t, `
+name     = var.name
+location = var.location
+tags     = null
+
+site_config {
+  always_on = true
+}
+`)
+	bd := NewBlockData()
+	bd.ParseAttributes(body)
+	bd.ParseBlocks(body)
+
+	var findings []ValidationFinding
+	got := bd.Validate("azurerm_linux_function_app", "root", schema, []string{"ignored"}, &findings)
+
+	// name, location and site_config with its always_on out of tags, https_only, identity and
+	// ftps_state.
+	want := Coverage{Configured: 4, Total: 8, Percent: 50}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("coverage mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := bd.Validate("azurerm_resource_group", "root", &SchemaBlock{}, nil, &findings); got.Percent != 100 {
+		t.Fatalf("a schema without configurable attributes should be fully covered, got %+v", got)
+	}
+}
+
 func parseHCLBody(t *testing.T, src string) *hclsyntax.Body {
 	t.Helper()
 
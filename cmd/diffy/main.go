@@ -0,0 +1,211 @@
+// Command diffy validates Terraform configurations against provider schemas.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloudnationhq/az-cn-go-diffy"
+)
+
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitError
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stderr)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "diffy: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitError
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: diffy <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  validate    validate a Terraform root and its submodules against provider schemas")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'diffy validate -h' for the flags of the validate command.")
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		root                string
+		excludedResources   listFlag
+		excludedDataSources listFlag
+		createIssue         bool
+		githubOwner         string
+		githubRepo          string
+		silent              bool
+		format              string
+		output              string
+		failOn              string
+		maxFindings         int
+		baseline            string
+	)
+
+	fs.StringVar(&root, "root", "", "path to the Terraform root (overridden by TERRAFORM_ROOT)")
+	fs.Var(&excludedResources, "exclude-resources", "comma-separated resource types to skip (repeatable)")
+	fs.Var(&excludedDataSources, "exclude-data-sources", "comma-separated data source types to skip (repeatable)")
+	fs.BoolVar(&createIssue, "github-issue", false, "create or update a GitHub issue with the findings (uses GITHUB_TOKEN)")
+	fs.StringVar(&githubOwner, "github-owner", "", "repository owner for the GitHub issue (defaults to GITHUB_REPOSITORY)")
+	fs.StringVar(&githubRepo, "github-repo", "", "repository name for the GitHub issue (defaults to GITHUB_REPOSITORY)")
+	fs.BoolVar(&silent, "silent", false, "suppress the findings output on stdout (a report file given with -output is still written)")
+	fs.StringVar(&format, "format", "text", "output format: text, json, sarif, junit, markdown, github or gitlab")
+	fs.StringVar(&output, "output", "", "write the report to this file instead of stdout")
+	fs.StringVar(&failOn, "fail-on", "any", "when to exit with 1: never, required, error, any, count or new")
+	fs.IntVar(&maxFindings, "max-findings", 0, "number of findings tolerated with -fail-on count")
+	fs.StringVar(&baseline, "baseline", "", "JSON report of an earlier run whose findings are tolerated with -fail-on new")
+
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: diffy validate [flags] [root]")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitError
+	}
+
+	if fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "diffy: expected at most one root argument, got %d\n", fs.NArg())
+		return exitError
+	}
+	if fs.NArg() == 1 {
+		if root != "" {
+			fmt.Fprintln(stderr, "diffy: root given both as argument and -root flag")
+			return exitError
+		}
+		root = fs.Arg(0)
+	}
+
+	options := []diffy.SchemaValidatorOption{
+		diffy.WithExcludedResources(excludedResources...),
+		diffy.WithExcludedDataSources(excludedDataSources...),
+		func(opts *diffy.SchemaValidatorOptions) {
+			opts.Logger = &writerLogger{w: stderr}
+			opts.Silent = silent
+			opts.GitHubOwner = githubOwner
+			opts.GitHubRepo = githubRepo
+		},
+	}
+	if root != "" {
+		options = append(options, diffy.WithTerraformRoot(root))
+	}
+	if createIssue {
+		options = append(options, diffy.WithGitHubIssueCreation())
+	}
+
+	mode, err := diffy.ParseFailureMode(failOn)
+	if err != nil {
+		fmt.Fprintf(stderr, "diffy: %v\n", err)
+		return exitError
+	}
+	policy := diffy.FailurePolicy{Mode: mode, Threshold: maxFindings}
+	if baseline != "" {
+		if mode != diffy.FailOnNew {
+			fmt.Fprintln(stderr, "diffy: -baseline requires -fail-on new")
+			return exitError
+		}
+		policy.Baseline, err = diffy.LoadBaseline(baseline)
+		if err != nil {
+			fmt.Fprintf(stderr, "diffy: %v\n", err)
+			return exitError
+		}
+	}
+	options = append(options, diffy.WithFailurePolicy(policy))
+
+	newReporter, ok := formats[format]
+	if !ok {
+		fmt.Fprintf(stderr, "diffy: unsupported output format %q\n", format)
+		return exitError
+	}
+
+	var out io.Writer = stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintf(stderr, "diffy: failed to create %s: %v\n", output, err)
+			return exitError
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if !silent || output != "" {
+		options = append(options, diffy.WithReporter(newReporter(out)))
+	}
+
+	if _, err := diffy.ValidateSchemaReport(options...); err != nil {
+		fmt.Fprintf(stderr, "diffy: %v\n", err)
+		var violation *diffy.PolicyViolationError
+		if errors.As(err, &violation) {
+			return exitFindings
+		}
+		return exitError
+	}
+	return exitOK
+}
+
+var formats = map[string]func(io.Writer) diffy.Reporter{
+	"text":     diffy.NewTextReporter,
+	"json":     diffy.NewJSONReporter,
+	"sarif":    diffy.NewSARIFReporter,
+	"junit":    diffy.NewJUnitReporter,
+	"markdown": diffy.NewMarkdownReporter,
+	"github":   diffy.NewGitHubAnnotationsReporter,
+	"gitlab":   diffy.NewGitLabCodeQualityReporter,
+}
+
+// listFlag collects comma-separated values across repeated flag occurrences.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// writerLogger keeps log lines off stdout so machine-readable output stays parseable.
+type writerLogger struct {
+	w io.Writer
+}
+
+func (l *writerLogger) Logf(format string, args ...any) {
+	fmt.Fprintf(l.w, format+"\n", args...)
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudnationhq/az-cn-go-diffy"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRunWithoutCommandPrintsUsage(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(nil, io.Discard, &stderr); code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr.String(), "Usage: diffy") {
+		t.Fatalf("expected usage output, got %q", stderr.String())
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"lint"}, io.Discard, &stderr); code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr.String(), `unknown command "lint"`) {
+		t.Fatalf("expected unknown command message, got %q", stderr.String())
+	}
+}
+
+func TestRunValidateRejectsUnknownFormat(t *testing.T) {
+	var stderr bytes.Buffer
+	code := run([]string{"validate", "-root", t.TempDir(), "-format", "yaml"}, io.Discard, &stderr)
+	if code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr.String(), `unsupported output format "yaml"`) {
+		t.Fatalf("expected format error, got %q", stderr.String())
+	}
+}
+
+func TestRunValidateRejectsDuplicateRoot(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"validate", "-root", "a", "b"}, io.Discard, &stderr); code != exitError {
+		t.Fatalf("expected exit code %d, got %d", exitError, code)
+	}
+}
+
+func TestRunValidateExitCodes(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	tests := []struct {
+		name     string
+		resource string
+		args     []string
+		want     int
+	}{
+		{
+			name:     "complete resource",
+			resource: "name     = \"rg\"\n  location = \"westeurope\"",
+			want:     exitOK,
+		},
+		{
+			name:     "missing required attribute",
+			resource: "name = \"rg\"",
+			want:     exitFindings,
+		},
+		{
+			name:     "excluded resource",
+			resource: "name = \"rg\"",
+			args:     []string{"-exclude-resources", "azurerm_resource_group"},
+			want:     exitOK,
+		},
+		{
+			name:     "undefined provider function",
+			resource: "name     = provider::azurerm::resource_group_name(\"rg\")\n  location = \"westeurope\"",
+			want:     exitFindings,
+		},
+		{
+			name:     "excluded resource calling undefined provider function",
+			resource: "name     = provider::azurerm::resource_group_name(\"rg\")\n  location = \"westeurope\"",
+			args:     []string{"-exclude-resources", "azurerm_resource_group"},
+			want:     exitOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeModule(t, root, tt.resource)
+
+			args := append([]string{"validate", "-silent"}, tt.args...)
+			args = append(args, root)
+
+			var stderr bytes.Buffer
+			if code := run(args, io.Discard, &stderr); code != tt.want {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tt.want, code, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunValidateJSONFormat(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	root := t.TempDir()
+	writeModule(t, root, "name = \"rg\"")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "-format", "json", root}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitFindings, code, stderr.String())
+	}
+
+	var report diffy.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, stdout.String())
+	}
+	if report.Summary.Findings != 1 || report.Findings[0].Name != "location" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	output := filepath.Join(t.TempDir(), "report.json")
+	stdout.Reset()
+	if code := run([]string{"validate", "-format", "json", "-output", output, root}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitFindings, code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout should stay empty when -output is set, got %q", stdout.String())
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("expected report file: %v", err)
+	}
+}
+
+func TestRunValidateSARIFFormat(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	root := t.TempDir()
+	writeModule(t, root, "name = \"rg\"")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "-format", "sarif", root}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitFindings, code, stderr.String())
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatalf("stdout is not a SARIF log: %v\n%s", err, stdout.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	if log.Runs[0].Results[0].RuleID != diffy.RuleMissingRequiredProperty.ID {
+		t.Fatalf("unexpected rule id %q", log.Runs[0].Results[0].RuleID)
+	}
+}
+
+func TestRunValidateJUnitFormat(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	root := t.TempDir()
+	writeModule(t, root, "name     = \"rg\"\n  location = \"westeurope\"")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "-format", "junit", root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), `<testcase name="azurerm_resource_group.rg" classname="root" file=`) {
+		t.Fatalf("expected a passing test case for the resource group, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunValidateTextOutputFile(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	root := t.TempDir()
+	writeModule(t, root, "name = \"rg\"")
+	output := filepath.Join(t.TempDir(), "findings.txt")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "-silent", "-output", output, root}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitFindings, code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout should stay empty, got %q", stdout.String())
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("expected report file: %v", err)
+	}
+	if !strings.Contains(string(data), "missing required property location") {
+		t.Fatalf("unexpected text report: %s", data)
+	}
+}
+
+func TestRunValidateFailurePolicy(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true},"location":{"required":true},"tags":{"optional":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	complete := "name     = \"rg\"\n  location = \"westeurope\""
+	missingLocation := "name = \"rg\""
+	unknownProperty := complete + "\n  tags = {}\n  lcoation = \"westeurope\""
+
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	baselineRoot := t.TempDir()
+	writeModule(t, baselineRoot, complete)
+	if code := run([]string{"validate", "-format", "json", "-output", baseline, "-fail-on", "never", baselineRoot}, io.Discard, io.Discard); code != exitOK {
+		t.Fatalf("failed to write baseline, exit code %d", code)
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		args     []string
+		want     int
+	}{
+		{name: "required passes on optional findings", resource: complete, args: []string{"-fail-on", "required"}, want: exitOK},
+		{name: "required fails on required findings", resource: missingLocation, args: []string{"-fail-on", "required"}, want: exitFindings},
+		{name: "required passes on unknown properties", resource: unknownProperty, args: []string{"-fail-on", "required"}, want: exitOK},
+		{name: "error fails on unknown properties", resource: unknownProperty, args: []string{"-fail-on", "error"}, want: exitFindings},
+		{name: "error passes on optional findings", resource: complete, args: []string{"-fail-on", "error"}, want: exitOK},
+		{name: "any fails on optional findings", resource: complete, args: []string{"-fail-on", "any"}, want: exitFindings},
+		{name: "never passes", resource: missingLocation, args: []string{"-fail-on", "never"}, want: exitOK},
+		{name: "count within threshold", resource: missingLocation, args: []string{"-fail-on", "count", "-max-findings", "2"}, want: exitOK},
+		{name: "count above threshold", resource: missingLocation, args: []string{"-fail-on", "count", "-max-findings", "1"}, want: exitFindings},
+		{name: "new tolerates baseline", resource: complete, args: []string{"-fail-on", "new", "-baseline", baseline}, want: exitOK},
+		{name: "new fails on new findings", resource: missingLocation, args: []string{"-fail-on", "new", "-baseline", baseline}, want: exitFindings},
+		{name: "unknown mode", resource: complete, args: []string{"-fail-on", "sometimes"}, want: exitError},
+		{name: "baseline without new", resource: complete, args: []string{"-baseline", baseline}, want: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeModule(t, root, tt.resource)
+
+			args := append([]string{"validate", "-silent"}, tt.args...)
+			args = append(args, root)
+
+			var stderr bytes.Buffer
+			if code := run(args, io.Discard, &stderr); code != tt.want {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tt.want, code, stderr.String())
+			}
+		})
+	}
+}
+
+func TestListFlagSplitsAndAccumulates(t *testing.T) {
+	var l listFlag
+	if err := l.Set("azurerm_resource_group, azurerm_key_vault"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if err := l.Set("azurerm_subnet,"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	want := listFlag{"azurerm_resource_group", "azurerm_key_vault", "azurerm_subnet"}
+	if diff := cmp.Diff(want, l); diff != "" {
+		t.Fatalf("listFlag mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunValidateChecksProviderAliases(t *testing.T) {
+	fakeTerraform(t, `{"provider_schemas":{"registry.terraform.io/hashicorp/azurerm":{"resource_schemas":{"azurerm_resource_group":{"block":{"attributes":{"name":{"required":true}},"block_types":{}}}},"data_source_schemas":{}}}}`)
+
+	tests := []struct {
+		name  string
+		alias string
+		want  int
+	}{
+		{name: "alias declared by provider block", alias: "secondary", want: exitOK},
+		{name: "undeclared alias", alias: "nope", want: exitFindings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			content := `
+terraform {
+  required_providers {
+    azurerm = {
+      source = "hashicorp/azurerm"
+    }
+  }
+}
+
+provider "azurerm" {
+  alias = "secondary"
+}
+
+resource "azurerm_resource_group" "rg" {
+  provider = azurerm.` + tt.alias + `
+  name     = "rg"
+}
+`
+			if err := os.WriteFile(filepath.Join(root, "main.tf"), []byte(content), 0o644); err != nil {
+				t.Fatalf("failed to write main.tf: %v", err)
+			}
+
+			var stderr bytes.Buffer
+			if code := run([]string{"validate", "-silent", root}, io.Discard, &stderr); code != tt.want {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tt.want, code, stderr.String())
+			}
+		})
+	}
+}
+
+func fakeTerraform(t *testing.T, schema string) {
+	t.Helper()
+
+	helperDir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "init" ]; then
+  exit 0
+fi
+if [ "$1" = "providers" ] && [ "$2" = "schema" ]; then
+  cat <<'EOF'
+` + schema + `
+EOF
+  exit 0
+fi
+echo "unexpected args: $@" >&2
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(helperDir, "terraform"), []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake terraform: %v", err)
+	}
+	t.Setenv("PATH", helperDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TERRAFORM_ROOT", "")
+}
+
+func writeModule(t *testing.T, dir, resourceBody string) {
+	t.Helper()
+
+	content := `
+terraform {
+  required_providers {
+    azurerm = {
+      source = "hashicorp/azurerm"
+    }
+  }
+}
+
+resource "azurerm_resource_group" "rg" {
+  ` + resourceBody + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+}
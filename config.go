@@ -2,6 +2,7 @@
 package diffy
 
 import (
+	"io"
 	"os"
 )
 
@@ -17,6 +18,10 @@ type SchemaValidatorOptions struct {
 	ExcludedDataSources []string
 	Parser              HCLParser
 	TerraformRunner     TerraformRunner
+	JSONReportPath      string
+	JSONReportWriter    io.Writer
+	Reporters           []Reporter
+	FailurePolicy       *FailurePolicy
 }
 
 type SchemaValidatorOption func(*SchemaValidatorOptions)
@@ -57,3 +62,29 @@ func WithTerraformRunner(runner TerraformRunner) SchemaValidatorOption {
 		opts.TerraformRunner = runner
 	}
 }
+
+// WithReporter adds reporters that receive the report of every run. When none are configured,
+// findings are written as text to stdout unless Silent is set.
+func WithReporter(reporters ...Reporter) SchemaValidatorOption {
+	return func(opts *SchemaValidatorOptions) {
+		opts.Reporters = append(opts.Reporters, reporters...)
+	}
+}
+
+func WithJSONReport(path string) SchemaValidatorOption {
+	return func(opts *SchemaValidatorOptions) {
+		opts.JSONReportPath = path
+	}
+}
+
+func WithJSONReportWriter(w io.Writer) SchemaValidatorOption {
+	return func(opts *SchemaValidatorOptions) {
+		opts.JSONReportWriter = w
+	}
+}
+
+func WithFailurePolicy(policy FailurePolicy) SchemaValidatorOption {
+	return func(opts *SchemaValidatorOptions) {
+		opts.FailurePolicy = &policy
+	}
+}
@@ -1,6 +1,7 @@
 package diffy
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -134,3 +135,46 @@ func TestOptionChaining(t *testing.T) {
 		t.Error("TerraformRunner not set correctly")
 	}
 }
+
+func TestWithJSONReport(t *testing.T) {
+	opts := &SchemaValidatorOptions{}
+
+	WithJSONReport("report.json")(opts)
+
+	if opts.JSONReportPath != "report.json" {
+		t.Errorf("WithJSONReport() = %s, want %s", opts.JSONReportPath, "report.json")
+	}
+}
+
+func TestWithJSONReportWriter(t *testing.T) {
+	opts := &SchemaValidatorOptions{}
+	var buf bytes.Buffer
+
+	WithJSONReportWriter(&buf)(opts)
+
+	if opts.JSONReportWriter != &buf {
+		t.Error("WithJSONReportWriter() should set JSONReportWriter")
+	}
+}
+
+func TestWithReporter(t *testing.T) {
+	opts := &SchemaValidatorOptions{}
+	var buf bytes.Buffer
+
+	WithReporter(NewTextReporter(&buf))(opts)
+	WithReporter(NewJSONReporter(&buf), NewSARIFReporter(&buf))(opts)
+
+	if len(opts.Reporters) != 3 {
+		t.Errorf("WithReporter() should accumulate reporters, got %d", len(opts.Reporters))
+	}
+}
+
+func TestWithFailurePolicy(t *testing.T) {
+	opts := &SchemaValidatorOptions{}
+
+	WithFailurePolicy(FailurePolicy{Mode: FailOnCount, Threshold: 3})(opts)
+
+	if opts.FailurePolicy == nil || opts.FailurePolicy.Mode != FailOnCount || opts.FailurePolicy.Threshold != 3 {
+		t.Errorf("WithFailurePolicy() = %+v", opts.FailurePolicy)
+	}
+}
@@ -11,7 +11,19 @@ import (
 	"sync"
 )
 
+// ValidateSchema validates the configured root and its submodules. When a FailurePolicy is set and
+// the run violates it, the findings are returned together with a *PolicyViolationError.
 func ValidateSchema(options ...SchemaValidatorOption) ([]ValidationFinding, error) {
+	report, err := ValidateSchemaReport(options...)
+	if report == nil {
+		return nil, err
+	}
+	return report.Findings, err
+}
+
+// ValidateSchemaReport is ValidateSchema returning the full report. A policy violation is reported
+// as a *PolicyViolationError next to a complete report.
+func ValidateSchemaReport(options ...SchemaValidatorOption) (*Report, error) {
 	opts := &SchemaValidatorOptions{
 		Logger:            &SimpleLogger{},
 		CreateGitHubIssue: false,
@@ -46,13 +58,36 @@ func ValidateSchema(options ...SchemaValidatorOption) ([]ValidationFinding, erro
 		return nil, fmt.Errorf("terraform root path not specified - set TERRAFORM_ROOT environment variable or use WithTerraformRoot option")
 	}
 
-	findings, err := validateProject(opts)
+	modules, findings, err := scanProject(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if !opts.Silent {
-		outputFindings(findings)
+	report := NewReport(opts, modules, findings)
+
+	if opts.FailurePolicy != nil {
+		result := opts.FailurePolicy.Evaluate(findings)
+		report.Policy = &result
+	}
+
+	reporters := opts.Reporters
+	if len(reporters) == 0 && !opts.Silent {
+		reporters = []Reporter{NewTextReporter(os.Stdout)}
+	}
+	if opts.JSONReportWriter != nil {
+		reporters = append(reporters, NewJSONReporter(opts.JSONReportWriter))
+	}
+
+	for _, reporter := range reporters {
+		if err := reporter.Report(report); err != nil {
+			return nil, fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if opts.JSONReportPath != "" {
+		if err := report.WriteJSONFile(opts.JSONReportPath); err != nil {
+			return nil, err
+		}
 	}
 
 	if opts.CreateGitHubIssue {
@@ -62,13 +97,22 @@ func ValidateSchema(options ...SchemaValidatorOption) ([]ValidationFinding, erro
 		}
 	}
 
-	return findings, nil
+	if report.Policy != nil && report.Policy.Failed {
+		return report, &PolicyViolationError{Result: *report.Policy}
+	}
+
+	return report, nil
 }
 
 func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error) {
+	_, findings, err := scanProject(opts)
+	return findings, err
+}
+
+func scanProject(opts *SchemaValidatorOptions) ([]ModuleReport, []ValidationFinding, error) {
 	absRoot, err := filepath.Abs(opts.TerraformRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", opts.TerraformRoot, err)
+		return nil, nil, fmt.Errorf("failed to resolve absolute path for %s: %w", opts.TerraformRoot, err)
 	}
 
 	parser := opts.Parser
@@ -81,7 +125,7 @@ func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error)
 		runner = NewTerraformRunner()
 	}
 
-	rootFindings, err := ValidateTerraformSchemaWithOptions(
+	rootFindings, rootEntities, err := validateModule(
 		opts.Logger,
 		absRoot,
 		"",
@@ -91,9 +135,11 @@ func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error)
 		opts.ExcludedDataSources,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	modules := []ModuleReport{{Path: absRoot, Root: true, Entities: nonNil(rootEntities)}}
+
 	var allFindings []ValidationFinding
 	allFindings = append(allFindings, rootFindings...)
 
@@ -108,20 +154,22 @@ func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error)
 
 		type moduleResult struct {
 			findings []ValidationFinding
+			entities []EntityReport
+			err      error
 		}
 
-		results := make(chan moduleResult, len(submodules))
+		results := make([]moduleResult, len(submodules))
 		sem := make(chan struct{}, concurrency)
 		var wg sync.WaitGroup
 
-		for _, module := range submodules {
+		for i, module := range submodules {
 			wg.Add(1)
-			go func(sm SubModule) {
+			go func(i int, sm SubModule) {
 				defer wg.Done()
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				findings, err := ValidateTerraformSchemaWithOptions(
+				findings, entities, err := validateModule(
 					opts.Logger,
 					sm.Path,
 					sm.Name,
@@ -132,18 +180,21 @@ func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error)
 				)
 				if err != nil {
 					opts.Logger.Logf("Failed to validate submodule %s: %v", sm.Name, err)
-					return
 				}
 
-				results <- moduleResult{findings: findings}
-			}(module)
+				results[i] = moduleResult{findings: findings, entities: entities, err: err}
+			}(i, module)
 		}
 
 		wg.Wait()
-		close(results)
 
-		for res := range results {
-			allFindings = append(allFindings, res.findings...)
+		for i, sm := range submodules {
+			module := ModuleReport{Name: sm.Name, Path: sm.Path, Entities: nonNil(results[i].entities)}
+			if results[i].err != nil {
+				module.Error = results[i].err.Error()
+			}
+			modules = append(modules, module)
+			allFindings = append(allFindings, results[i].findings...)
 		}
 
 		for _, sm := range submodules {
@@ -155,20 +206,7 @@ func validateProject(opts *SchemaValidatorOptions) ([]ValidationFinding, error)
 
 	deduplicatedFindings := DeduplicateFindings(allFindings)
 
-	return deduplicatedFindings, nil
-}
-
-func outputFindings(findings []ValidationFinding) {
-	if len(findings) == 0 {
-		fmt.Println("No validation findings.")
-		return
-	}
-
-	fmt.Printf("Found %d issues:\n", len(findings))
-
-	for _, finding := range findings {
-		fmt.Println(FormatFinding(finding))
-	}
+	return modules, deduplicatedFindings, nil
 }
 
 func createGitHubIssue(ctx context.Context, opts *SchemaValidatorOptions, findings []ValidationFinding) error {
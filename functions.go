@@ -0,0 +1,250 @@
+// Package diffy provides validation of calls to provider-defined functions
+package diffy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FunctionSignature is a provider-defined function from the functions section of the schema.
+type FunctionSignature struct {
+	Description       string              `json:"description"`
+	Summary           string              `json:"summary"`
+	ReturnType        cty.Type            `json:"return_type"`
+	Parameters        []FunctionParameter `json:"parameters"`
+	VariadicParameter *FunctionParameter  `json:"variadic_parameter"`
+}
+
+type FunctionParameter struct {
+	Name        string   `json:"name"`
+	Type        cty.Type `json:"type"`
+	Description string   `json:"description"`
+	IsNullable  bool     `json:"is_nullable"`
+}
+
+// FunctionCall is a call to a provider-defined function such as provider::azurerm::parse_resource_id.
+// Owner is the resource type, locals or output.<name> the call appears in, Entity the name or provider
+// alias of that entity, and Path the block path and Attribute the attribute holding the expression.
+// Check names the enclosing check block of a scoped data source.
+type FunctionCall struct {
+	Provider    string
+	Function    string
+	Owner       string
+	Entity      string
+	Kind        EntityKind
+	Check       string
+	Path        string
+	Attribute   string
+	Args        int
+	ExpandFinal bool
+	Range       SourceRange
+}
+
+// Name is the full name the function is called by.
+func (call FunctionCall) Name() string {
+	return "provider::" + call.Provider + "::" + call.Function
+}
+
+// FunctionCallParser is implemented by parsers that can list calls to provider-defined functions.
+// Validation of function calls is skipped for parsers that do not implement it.
+type FunctionCallParser interface {
+	ParseFunctionCalls(ctx context.Context, filenames []string) ([]FunctionCall, error)
+}
+
+// ParseFunctionCalls walks the expressions of resources, data sources, ephemeral resources, provider
+// blocks, locals and outputs and returns every call to a provider-defined function.
+func (parser *DefaultHCLParser) ParseFunctionCalls(_ context.Context, files []string) ([]FunctionCall, error) {
+	var calls []FunctionCall
+
+	for _, filename := range files {
+		f, err := parser.parseHCLFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			return nil, &ParseError{
+				File:    filename,
+				Message: "invalid HCL body type",
+			}
+		}
+
+		for _, blk := range body.Blocks {
+			calls = append(calls, functionCallsInBlock(blk)...)
+		}
+	}
+
+	return calls, nil
+}
+
+func functionCallsInBlock(blk *hclsyntax.Block) []FunctionCall {
+	var calls []FunctionCall
+
+	switch {
+	case (blk.Type == "resource" || blk.Type == "data" || blk.Type == "ephemeral") && len(blk.Labels) >= 2:
+		kind := EntityKind(blk.Type)
+		if blk.Type == "data" {
+			kind = KindDataSource
+		}
+		collectFunctionCalls(blk.Body, blk.Labels[0], blk.Labels[1], kind, "root", &calls)
+	case blk.Type == "provider" && len(blk.Labels) == 1:
+		alias := ""
+		if attr, ok := blk.Body.Attributes["alias"]; ok {
+			if value, ok := constantValue(attr.Expr); ok && !value.IsNull() && value.Type() == cty.String {
+				alias = value.AsString()
+			}
+		}
+		collectFunctionCalls(blk.Body, blk.Labels[0], alias, KindProvider, "root", &calls)
+	case blk.Type == "locals":
+		collectFunctionCalls(blk.Body, "locals", "", KindLocals, "root", &calls)
+	case blk.Type == "output" && len(blk.Labels) == 1:
+		collectFunctionCalls(blk.Body, "output."+blk.Labels[0], "", KindOutput, "root", &calls)
+	case blk.Type == "check" && len(blk.Labels) == 1:
+		for _, inner := range blk.Body.Blocks {
+			if inner.Type != "data" {
+				continue
+			}
+			for _, call := range functionCallsInBlock(inner) {
+				call.Check = blk.Labels[0]
+				calls = append(calls, call)
+			}
+		}
+	}
+
+	return calls
+}
+
+func collectFunctionCalls(body *hclsyntax.Body, owner, entity string, kind EntityKind, path string, calls *[]FunctionCall) {
+	for _, name := range slices.Sorted(maps.Keys(body.Attributes)) {
+		hclsyntax.VisitAll(body.Attributes[name].Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.FunctionCallExpr)
+			if !ok {
+				return nil
+			}
+
+			parts := strings.Split(expr.Name, "::")
+			if len(parts) != 3 || parts[0] != "provider" {
+				return nil
+			}
+
+			*calls = append(*calls, FunctionCall{
+				Provider:    parts[1],
+				Function:    parts[2],
+				Owner:       owner,
+				Entity:      entity,
+				Kind:        kind,
+				Path:        path,
+				Attribute:   name,
+				Args:        len(expr.Args),
+				ExpandFinal: expr.ExpandFinal,
+				Range:       NewSourceRange(expr.Range()),
+			})
+			return nil
+		})
+	}
+
+	for _, blk := range body.Blocks {
+		switch {
+		case blk.Type == "dynamic" && len(blk.Labels) == 1:
+			collectFunctionCalls(blk.Body, owner, entity, kind, path+"."+blk.Labels[0], calls)
+		case blk.Type == "content":
+			collectFunctionCalls(blk.Body, owner, entity, kind, path, calls)
+		default:
+			collectFunctionCalls(blk.Body, owner, entity, kind, path+"."+blk.Type, calls)
+		}
+	}
+}
+
+// validateFunctionCalls reports calls to functions the provider does not define and calls whose
+// argument count does not fit the signature. Each finding is also added to the entity making the
+// call; locals and outputs have no entity and are only reported at module level. Calls to providers
+// without a config are logged and skipped, as their schema is unknown.
+func validateFunctionCalls(
+	logger Logger,
+	calls []FunctionCall,
+	schema TerraformSchema,
+	providers map[string]ProviderConfig,
+	entities []EntityReport,
+	dir, submoduleName string,
+) []ValidationFinding {
+	var findings []ValidationFinding
+
+	for _, call := range calls {
+		cfg, ok := providers[call.Provider]
+		if !ok {
+			logger.Logf("No provider config for function %s in %s", call.Name(), dir)
+			continue
+		}
+
+		pSchema, ok := schema.ProviderSchemas[cfg.Source]
+		if !ok {
+			logger.Logf("No provider schema found for source %s in %s", cfg.Source, dir)
+			continue
+		}
+
+		detail := ""
+		if signature, ok := pSchema.Functions[call.Function]; !ok {
+			detail = fmt.Sprintf("calls %s, which provider %s does not define", call.Name(), cfg.Source)
+		} else if want, ok := signature.acceptsArgs(call.Args, call.ExpandFinal); !ok {
+			detail = fmt.Sprintf("calls %s with %d %s, want %s", call.Name(), call.Args, plural(call.Args, "argument", "arguments"), want)
+		} else {
+			continue
+		}
+
+		finding := ValidationFinding{
+			ResourceType:  call.Owner,
+			Path:          call.Path,
+			Name:          call.Attribute,
+			Category:      CategoryFunction,
+			Kind:          call.Kind,
+			Check:         call.Check,
+			SubmoduleName: submoduleName,
+			Location:      call.Range,
+			Detail:        detail,
+		}
+		findings = append(findings, finding)
+
+		for i, entity := range entities {
+			if entity.Type == call.Owner && entity.Name == call.Entity && entity.Kind.orDefault() == call.Kind.orDefault() && entity.Check == call.Check {
+				entities[i].Findings = append(entities[i].Findings, finding)
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+// acceptsArgs reports whether a call with the given number of arguments fits the signature, and
+// otherwise describes the expected count. A call that expands its final argument with ... may pass
+// any number of trailing arguments and is only checked for too many fixed ones.
+func (signature *FunctionSignature) acceptsArgs(args int, expandFinal bool) (string, bool) {
+	fixed := len(signature.Parameters)
+
+	if signature.VariadicParameter != nil {
+		if expandFinal || args >= fixed {
+			return "", true
+		}
+		return fmt.Sprintf("at least %d", fixed), false
+	}
+
+	if expandFinal {
+		if args-1 <= fixed {
+			return "", true
+		}
+		return fmt.Sprintf("%d", fixed), false
+	}
+
+	if args == fixed {
+		return "", true
+	}
+	return fmt.Sprintf("%d", fixed), false
+}
@@ -0,0 +1,274 @@
+package diffy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestProviderSchemaDecodesFunctions(t *testing.T) {
+	data := `{
+		"functions": {
+			"normalise_resource_id": {
+				"summary": "normalise_resource_id",
+				"return_type": "string",
+				"parameters": [{"name": "id", "type": "string"}]
+			},
+			"join_ids": {
+				"return_type": "string",
+				"parameters": [{"name": "separator", "type": "string"}],
+				"variadic_parameter": {"name": "ids", "type": "string", "is_nullable": true}
+			}
+		}
+	}`
+
+	var schema ProviderSchema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatalf("failed to decode provider schema: %v", err)
+	}
+
+	normalise := schema.Functions["normalise_resource_id"]
+	if normalise == nil || !normalise.ReturnType.Equals(cty.String) || len(normalise.Parameters) != 1 {
+		t.Fatalf("unexpected normalise_resource_id signature %+v", normalise)
+	}
+	join := schema.Functions["join_ids"]
+	if join == nil || join.VariadicParameter == nil || !join.VariadicParameter.IsNullable {
+		t.Fatalf("unexpected join_ids signature %+v", join)
+	}
+}
+
+func TestParseFunctionCalls(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	content := `resource "azurerm_subnet" "subnet" {
+  name                 = provider::azurerm::parse_resource_id(var.subnet_id).resource_name
+  virtual_network_name = upper(var.vnet_name)
+
+  dynamic "delegation" {
+    for_each = var.delegations
+    content {
+      name = provider::azurerm::normalise_resource_id(delegation.value.id, "extra")
+    }
+  }
+}
+
+locals {
+  ids = provider::azurerm::join_ids(",", var.ids...)
+}
+
+output "vnet" {
+  value = provider::azurerm::normalise_resource_id(var.vnet_id)
+}
+
+check "health" {
+  data "azurerm_client_config" "current" {
+    tenant_id = provider::azurerm::normalise_resource_id(var.tenant_id)
+  }
+}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	calls, err := NewHCLParser().ParseFunctionCalls(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseFunctionCalls() error = %v", err)
+	}
+
+	want := []FunctionCall{
+		{Provider: "azurerm", Function: "parse_resource_id", Owner: "azurerm_subnet", Entity: "subnet", Kind: KindResource, Path: "root", Attribute: "name", Args: 1},
+		{Provider: "azurerm", Function: "normalise_resource_id", Owner: "azurerm_subnet", Entity: "subnet", Kind: KindResource, Path: "root.delegation", Attribute: "name", Args: 2},
+		{Provider: "azurerm", Function: "join_ids", Owner: "locals", Kind: KindLocals, Path: "root", Attribute: "ids", Args: 2, ExpandFinal: true},
+		{Provider: "azurerm", Function: "normalise_resource_id", Owner: "output.vnet", Kind: KindOutput, Path: "root", Attribute: "value", Args: 1},
+		{Provider: "azurerm", Function: "normalise_resource_id", Owner: "azurerm_client_config", Entity: "current", Kind: KindDataSource, Check: "health", Path: "root", Attribute: "tenant_id", Args: 1},
+	}
+	if diff := cmp.Diff(want, calls, cmpopts.IgnoreFields(FunctionCall{}, "Range")); diff != "" {
+		t.Fatalf("function calls mismatch (-want +got):\n%s", diff)
+	}
+	if calls[0].Range.Start.Line != 2 {
+		t.Errorf("expected call range on line 2, got %+v", calls[0].Range)
+	}
+}
+
+func TestValidateFunctionCalls(t *testing.T) {
+	source := "registry.terraform.io/hashicorp/azurerm"
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			source: {
+				Functions: map[string]*FunctionSignature{
+					"normalise_resource_id": {
+						Parameters: []FunctionParameter{{Name: "id", Type: cty.String}},
+					},
+					"join_ids": {
+						Parameters:        []FunctionParameter{{Name: "separator", Type: cty.String}},
+						VariadicParameter: &FunctionParameter{Name: "ids", Type: cty.String},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{"azurerm": {Source: source}}
+
+	calls := []FunctionCall{
+		{Provider: "azurerm", Function: "normalise_resource_id", Owner: "azurerm_subnet", Path: "root", Attribute: "name", Args: 1},
+		{Provider: "azurerm", Function: "normalise_resource_id", Owner: "output.vnet", Path: "root", Attribute: "value", Args: 2},
+		{Provider: "azurerm", Function: "parse_resource_id", Owner: "azurerm_client_config", Kind: KindDataSource, Check: "health", Path: "root", Attribute: "tenant_id", Args: 1},
+		{Provider: "azurerm", Function: "parse_resource_id", Owner: "locals", Path: "root", Attribute: "subnet", Args: 1},
+		{Provider: "azurerm", Function: "join_ids", Owner: "locals", Path: "root", Attribute: "ids", Args: 3},
+		{Provider: "azurerm", Function: "join_ids", Owner: "locals", Path: "root", Attribute: "none", Args: 0},
+		{Provider: "azapi", Function: "build_resource_id", Owner: "locals", Path: "root", Attribute: "azapi", Args: 1},
+	}
+
+	findings := validateFunctionCalls(&SimpleLogger{}, calls, schema, providers, nil, ".", "network")
+
+	want := []ValidationFinding{
+		{
+			ResourceType:  "output.vnet",
+			Path:          "root",
+			Name:          "value",
+			Category:      CategoryFunction,
+			SubmoduleName: "network",
+			Detail:        "calls provider::azurerm::normalise_resource_id with 2 arguments, want 1",
+		},
+		{
+			ResourceType:  "azurerm_client_config",
+			Path:          "root",
+			Name:          "tenant_id",
+			Category:      CategoryFunction,
+			Kind:          KindDataSource,
+			Check:         "health",
+			SubmoduleName: "network",
+			Detail:        "calls provider::azurerm::parse_resource_id, which provider registry.terraform.io/hashicorp/azurerm does not define",
+		},
+		{
+			ResourceType:  "locals",
+			Path:          "root",
+			Name:          "subnet",
+			Category:      CategoryFunction,
+			SubmoduleName: "network",
+			Detail:        "calls provider::azurerm::parse_resource_id, which provider registry.terraform.io/hashicorp/azurerm does not define",
+		},
+		{
+			ResourceType:  "locals",
+			Path:          "root",
+			Name:          "none",
+			Category:      CategoryFunction,
+			SubmoduleName: "network",
+			Detail:        "calls provider::azurerm::join_ids with 0 arguments, want at least 1",
+		},
+	}
+	if diff := cmp.Diff(want, findings); diff != "" {
+		t.Fatalf("findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateModuleAttachesFunctionCallFindings(t *testing.T) {
+	findings, entities := validateFunctionCallModule(t)
+
+	owners := map[string]int{}
+	for _, finding := range findings {
+		owners[finding.ResourceType]++
+	}
+	if diff := cmp.Diff(map[string]int{"azurerm_subnet": 1, "locals": 1, "output.subnet": 1}, owners); diff != "" {
+		t.Fatalf("module findings mismatch (-want +got):\n%s", diff)
+	}
+
+	byName := map[string][]ValidationFinding{}
+	for _, entity := range entities {
+		byName[entity.Name] = entity.Findings
+	}
+	if got := byName["a"]; len(got) != 1 || got[0].Category != CategoryFunction || got[0].Name != "name" {
+		t.Fatalf("expected the call to be attached to azurerm_subnet.a, got %+v", got)
+	}
+	if got := byName["b"]; len(got) != 0 {
+		t.Fatalf("expected no findings on azurerm_subnet.b, got %+v", got)
+	}
+}
+
+func validateFunctionCallModule(t *testing.T) ([]ValidationFinding, []EntityReport) {
+	t.Helper()
+
+	dir := t.TempDir()
+	content := `terraform {
+  required_providers {
+    azurerm = {
+      source = "hashicorp/azurerm"
+    }
+  }
+}
+
+resource "azurerm_subnet" "a" {
+  name = provider::azurerm::parse_id(var.subnet_id)
+}
+
+resource "azurerm_subnet" "b" {
+  name = "b"
+}
+
+locals {
+  subnet = provider::azurerm::parse_id(var.subnet_id)
+}
+
+output "subnet" {
+  value = provider::azurerm::parse_id(var.subnet_id)
+}
+`
+	writeFile(t, filepath.Join(dir, "main.tf"), content)
+
+	runner := &stubRunner{
+		schema: &TerraformSchema{
+			ProviderSchemas: map[string]*ProviderSchema{
+				"registry.terraform.io/hashicorp/azurerm": {
+					ResourceSchemas: map[string]*ResourceSchema{
+						"azurerm_subnet": {
+							Block: &SchemaBlock{
+								Attributes: map[string]*SchemaAttribute{"name": {Optional: true}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	findings, entities, err := validateModule(&SimpleLogger{}, dir, "", NewHCLParser(), runner, nil, nil)
+	if err != nil {
+		t.Fatalf("validateModule returned error: %v", err)
+	}
+	return findings, entities
+}
+
+func TestFunctionSignatureAcceptsArgs(t *testing.T) {
+	fixed := &FunctionSignature{Parameters: make([]FunctionParameter, 2)}
+	variadic := &FunctionSignature{Parameters: make([]FunctionParameter, 1), VariadicParameter: &FunctionParameter{}}
+
+	tests := []struct {
+		name        string
+		signature   *FunctionSignature
+		args        int
+		expandFinal bool
+		want        bool
+	}{
+		{name: "exact", signature: fixed, args: 2, want: true},
+		{name: "too few", signature: fixed, args: 1},
+		{name: "too many", signature: fixed, args: 3},
+		{name: "expanded final argument", signature: fixed, args: 1, expandFinal: true, want: true},
+		{name: "expanded after too many", signature: fixed, args: 4, expandFinal: true},
+		{name: "variadic without extra", signature: variadic, args: 1, want: true},
+		{name: "variadic with extra", signature: variadic, args: 4, want: true},
+		{name: "variadic too few", signature: variadic, args: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := tt.signature.acceptsArgs(tt.args, tt.expandFinal); got != tt.want {
+				t.Errorf("acceptsArgs(%d, %v) = %v, want %v", tt.args, tt.expandFinal, got, tt.want)
+			}
+		})
+	}
+}
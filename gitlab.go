@@ -0,0 +1,66 @@
+// Package diffy provides GitLab Code Quality export of validation findings
+package diffy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type gitLabCodeQualityIssue struct {
+	Description string                    `json:"description"`
+	CheckName   string                    `json:"check_name"`
+	Fingerprint string                    `json:"fingerprint"`
+	Severity    string                    `json:"severity"`
+	Location    gitLabCodeQualityLocation `json:"location"`
+}
+
+type gitLabCodeQualityLocation struct {
+	Path  string                 `json:"path"`
+	Lines gitLabCodeQualityLines `json:"lines"`
+}
+
+type gitLabCodeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// NewGitLabCodeQualityReporter writes findings in the GitLab Code Quality format, for the merge
+// request widget.
+func NewGitLabCodeQualityReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		return WriteGitLabCodeQuality(w, report.Findings)
+	})
+}
+
+func WriteGitLabCodeQuality(w io.Writer, findings []ValidationFinding) error {
+	issues := make([]gitLabCodeQualityIssue, 0, len(findings))
+	for _, finding := range findings {
+		severity := "minor"
+		if finding.Required {
+			severity = "major"
+		}
+
+		line := finding.Location.Start.Line
+		if line == 0 {
+			line = 1
+		}
+
+		issues = append(issues, gitLabCodeQualityIssue{
+			Description: FormatFinding(finding),
+			CheckName:   RuleForFinding(finding).Name,
+			Fingerprint: FindingFingerprint(finding),
+			Severity:    severity,
+			Location: gitLabCodeQualityLocation{
+				Path:  finding.Location.RelativeFilename(),
+				Lines: gitLabCodeQualityLines{Begin: line},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(issues); err != nil {
+		return fmt.Errorf("failed to encode GitLab Code Quality report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package diffy
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteGitLabCodeQuality(t *testing.T) {
+	required := ValidationFinding{
+		ResourceType: "azurerm_key_vault",
+		Path:         "root",
+		Name:         "sku_name",
+		Required:     true,
+		Location: SourceRange{
+			Filename: "modules/vault/main.tf",
+			Start:    SourcePos{Line: 7, Column: 1},
+			End:      SourcePos{Line: 20, Column: 2},
+		},
+	}
+	optional := ValidationFinding{ResourceType: "azurerm_subnet", Path: "root", Name: "delegation", IsBlock: true}
+
+	var buf bytes.Buffer
+	if err := WriteGitLabCodeQuality(&buf, []ValidationFinding{required, optional}); err != nil {
+		t.Fatalf("WriteGitLabCodeQuality returned error: %v", err)
+	}
+
+	var got []gitLabCodeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	want := []gitLabCodeQualityIssue{
+		{
+			Description: FormatFinding(required),
+			CheckName:   RuleMissingRequiredProperty.Name,
+			Fingerprint: FindingFingerprint(required),
+			Severity:    "major",
+			Location: gitLabCodeQualityLocation{
+				Path:  "modules/vault/main.tf",
+				Lines: gitLabCodeQualityLines{Begin: 7},
+			},
+		},
+		{
+			Description: FormatFinding(optional),
+			CheckName:   RuleMissingOptionalBlock.Name,
+			Fingerprint: FindingFingerprint(optional),
+			Severity:    "minor",
+			Location: gitLabCodeQualityLocation{
+				Lines: gitLabCodeQualityLines{Begin: 1},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("code quality mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGitLabCodeQualityReporterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewGitLabCodeQualityReporter(&buf).Report(NewReport(&SchemaValidatorOptions{}, nil, nil)); err != nil {
+		t.Fatalf("Report returned error: %v", err)
+	}
+	if got := bytes.TrimSpace(buf.Bytes()); string(got) != "[]" {
+		t.Fatalf("expected an empty JSON array, got %q", got)
+	}
+}
@@ -3,13 +3,13 @@ module github.com/cloudnationhq/az-cn-go-diffy
 go 1.24.1
 
 require (
+	github.com/agext/levenshtein v1.2.1
 	github.com/google/go-cmp v0.6.0
 	github.com/hashicorp/hcl/v2 v2.23.0
 	github.com/zclconf/go-cty v1.17.0
 )
 
 require (
-	github.com/agext/levenshtein v1.2.1 // indirect
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	golang.org/x/mod v0.24.0 // indirect
@@ -27,3 +27,7 @@ type Logger interface {
 type RepositoryInfoProvider interface {
 	GetRepoInfo() (owner, name string)
 }
+
+type Reporter interface {
+	Report(report *Report) error
+}
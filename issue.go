@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -38,49 +37,7 @@ func (manager *GitHubIssueManager) CreateOrUpdateIssue(ctx context.Context, find
 		return nil
 	}
 
-	dedup := make(map[string]ValidationFinding)
-
-	for _, finding := range findings {
-		key := fmt.Sprintf("%s|%s|%s|%v|%v|%s",
-			finding.ResourceType,
-			strings.ReplaceAll(finding.Path, "root.", ""),
-			finding.Name,
-			finding.IsBlock,
-			finding.IsDataSource,
-			finding.SubmoduleName,
-		)
-		dedup[key] = finding
-	}
-
-	var newBody bytes.Buffer
-
-	for _, finding := range dedup {
-		cleanPath := strings.ReplaceAll(finding.Path, "root.", "")
-		status := "optional"
-		if finding.Required {
-			status = "required"
-		}
-
-		itemType := "property"
-		if finding.IsBlock {
-			itemType = "block"
-		}
-
-		entityType := "resource"
-		if finding.IsDataSource {
-			entityType = "data source"
-		}
-
-		if finding.SubmoduleName == "" {
-			fmt.Fprintf(&newBody, "`%s`: missing %s %s `%s` in `%s` (%s)\n\n",
-				finding.ResourceType, status, itemType, finding.Name, cleanPath, entityType,
-			)
-		} else {
-			fmt.Fprintf(&newBody, "`%s`: missing %s %s `%s` in `%s` in submodule `%s` (%s)\n\n",
-				finding.ResourceType, status, itemType, finding.Name, cleanPath, finding.SubmoduleName, entityType,
-			)
-		}
-	}
+	unique := DeduplicateFindings(findings)
 
 	title := "Generated schema validation"
 	issueNum, _, err := manager.findExistingIssue(ctx, title)
@@ -88,7 +45,7 @@ func (manager *GitHubIssueManager) CreateOrUpdateIssue(ctx context.Context, find
 		return err
 	}
 
-	finalBody := newBody.String()
+	finalBody := RenderMarkdown(unique)
 	if issueNum > 0 {
 		return manager.updateIssue(ctx, issueNum, finalBody)
 	}
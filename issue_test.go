@@ -58,6 +58,36 @@ func TestCreateOrUpdateIssue_CreatesNew(t *testing.T) {
 	}
 }
 
+func TestCreateOrUpdateIssue_KeepsDistinctCategoriesAndChecks(t *testing.T) {
+	var calls []recordedCall
+	client := newStubHTTPClient(t, &calls, []httpHandlerStep{
+		{method: "GET", path: "/repos/o/r/issues", status: http.StatusOK, body: `[]`},
+		{method: "POST", path: "/repos/o/r/issues", status: http.StatusCreated},
+	})
+
+	manager := &GitHubIssueManager{
+		GitHubConfig: GitHubConfig{RepoOwner: "o", RepoName: "r", Token: "TOKEN"},
+		Client:       client,
+	}
+
+	findings := []ValidationFinding{
+		{ResourceType: "r1", Path: "root", Name: "foo", Required: true},
+		{ResourceType: "r1", Path: "root", Name: "foo", Category: CategoryType, Required: true},
+		{ResourceType: "d1", Path: "root", Name: "bar", Kind: KindDataSource},
+		{ResourceType: "d1", Path: "root", Name: "bar", Kind: KindDataSource, Check: "health"},
+		{ResourceType: "d1", Path: "root", Name: "bar", Kind: KindDataSource, Check: "health"},
+	}
+
+	if err := manager.CreateOrUpdateIssue(context.Background(), findings); err != nil {
+		t.Fatalf("CreateOrUpdateIssue returned error: %v", err)
+	}
+
+	body := calls[1].body.String()
+	if !strings.Contains(body, "**4** findings") {
+		t.Fatalf("expected 4 distinct findings in the issue body, got %q", body)
+	}
+}
+
 func TestCreateOrUpdateIssue_UpdatesExisting(t *testing.T) {
 	var calls []recordedCall
 	client := newStubHTTPClient(t, &calls, []httpHandlerStep{
@@ -0,0 +1,115 @@
+// Package diffy provides JUnit XML export of validation results
+package diffy
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	File      string         `xml:"file,attr,omitempty"`
+	Line      int            `xml:"line,attr,omitempty"`
+	Failures  []junitFailure `xml:"failure"`
+	Error     *junitFailure  `xml:"error"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes one test suite per module and one test case per validated resource or data source.
+// Entities without findings are emitted as passing cases.
+func WriteJUnit(w io.Writer, report *Report) error {
+	suites := junitTestSuites{Name: "diffy"}
+
+	for _, module := range report.Modules {
+		suite := junitTestSuite{Name: junitSuiteName(module)}
+
+		for _, entity := range module.Entities {
+			testCase := junitTestCase{
+				Name:      junitCaseName(entity),
+				ClassName: suite.Name,
+				File:      entity.Location.RelativeFilename(),
+				Line:      entity.Location.Start.Line,
+			}
+			for _, finding := range entity.Findings {
+				message := FormatFinding(finding)
+				testCase.Failures = append(testCase.Failures, junitFailure{
+					Message: message,
+					Type:    RuleForFinding(finding).Name,
+					Text:    message,
+				})
+			}
+			if len(testCase.Failures) > 0 {
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		if module.Error != "" {
+			suite.Errors++
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "validate",
+				ClassName: suite.Name,
+				Error:     &junitFailure{Message: module.Error, Type: "ValidationError", Text: module.Error},
+			})
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+func junitSuiteName(module ModuleReport) string {
+	if module.Root || module.Name == "" {
+		return "root"
+	}
+	return "modules/" + module.Name
+}
+
+func junitCaseName(entity EntityReport) string {
+	address := entity.Kind.orDefault().Address(entity.Type, entity.Name)
+	if entity.Check != "" {
+		return "check." + entity.Check + "." + address
+	}
+	return address
+}
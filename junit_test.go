@@ -0,0 +1,120 @@
+package diffy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	missing := ValidationFinding{ResourceType: "azurerm_subnet", Path: "root", Name: "delegation", IsBlock: true, SubmoduleName: "network"}
+	report := NewReport(&SchemaValidatorOptions{}, []ModuleReport{
+		{
+			Root: true,
+			Entities: []EntityReport{
+				{Type: "azurerm_resource_group", Name: "rg", Findings: []ValidationFinding{}},
+				{Type: "azurerm_client_config", Name: "current", Kind: KindDataSource, Findings: []ValidationFinding{}},
+			},
+		},
+		{
+			Name: "network",
+			Entities: []EntityReport{
+				{Type: "azurerm_subnet", Name: "a", Findings: []ValidationFinding{missing}},
+				{Type: "azurerm_subnet", Name: "b", Findings: []ValidationFinding{}},
+			},
+		},
+		{
+			Name:  "broken",
+			Error: "terraform init failed",
+		},
+	}, []ValidationFinding{missing})
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, report); err != nil {
+		t.Fatalf("WriteJUnit returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Fatalf("expected XML header, got %q", buf.String())
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+
+	if suites.Tests != 5 || suites.Failures != 1 || suites.Errors != 1 {
+		t.Fatalf("unexpected totals: tests=%d failures=%d errors=%d", suites.Tests, suites.Failures, suites.Errors)
+	}
+
+	var names []string
+	for _, suite := range suites.Suites {
+		names = append(names, suite.Name)
+	}
+	if diff := cmp.Diff([]string{"root", "modules/network", "modules/broken"}, names); diff != "" {
+		t.Fatalf("suite names mismatch (-want +got):\n%s", diff)
+	}
+
+	root := suites.Suites[0]
+	if root.TestCases[0].Name != "azurerm_resource_group.rg" || root.TestCases[1].Name != "data.azurerm_client_config.current" {
+		t.Fatalf("unexpected root test cases: %+v", root.TestCases)
+	}
+	if len(root.TestCases[0].Failures) != 0 {
+		t.Fatalf("entities without findings should pass")
+	}
+
+	failing := suites.Suites[1].TestCases[0]
+	if len(failing.Failures) != 1 {
+		t.Fatalf("expected one failure, got %d", len(failing.Failures))
+	}
+	if failing.Failures[0].Message != FormatFinding(missing) || failing.Failures[0].Type != RuleMissingOptionalBlock.Name {
+		t.Fatalf("unexpected failure: %+v", failing.Failures[0])
+	}
+
+	broken := suites.Suites[2]
+	if broken.Errors != 1 || broken.TestCases[0].Error == nil {
+		t.Fatalf("module errors should be reported as an error case, got %+v", broken)
+	}
+}
+
+func TestJUnitCaseNameIncludesCheck(t *testing.T) {
+	entity := EntityReport{Type: "http", Name: "health", Kind: KindDataSource, Check: "endpoint"}
+	if got := junitCaseName(entity); got != "check.endpoint.data.http.health" {
+		t.Fatalf("unexpected case name %q", got)
+	}
+
+	entity = EntityReport{Type: "azurerm_key_vault_secret", Name: "password", Kind: KindEphemeral}
+	if got := junitCaseName(entity); got != "ephemeral.azurerm_key_vault_secret.password" {
+		t.Fatalf("unexpected case name %q", got)
+	}
+}
+
+func TestWriteJUnitIncludesFunctionCallFindings(t *testing.T) {
+	findings, entities := validateFunctionCallModule(t)
+	report := NewReport(&SchemaValidatorOptions{}, []ModuleReport{{Root: true, Entities: entities}}, findings)
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, report); err != nil {
+		t.Fatalf("WriteJUnit returned error: %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+
+	failures := map[string][]junitFailure{}
+	for _, testCase := range suites.Suites[0].TestCases {
+		failures[testCase.Name] = testCase.Failures
+	}
+	got := failures["azurerm_subnet.a"]
+	if len(got) != 1 || got[0].Type != RuleInvalidFunctionCall.Name {
+		t.Fatalf("expected a function call failure on azurerm_subnet.a, got %+v", got)
+	}
+	if len(failures["azurerm_subnet.b"]) != 0 {
+		t.Fatalf("expected azurerm_subnet.b to pass, got %+v", failures["azurerm_subnet.b"])
+	}
+}
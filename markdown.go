@@ -0,0 +1,121 @@
+// Package diffy provides Markdown rendering of validation findings
+package diffy
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// RenderMarkdown renders findings as collapsible sections per module and resource type, each holding
+// a table of missing attributes and blocks. The output has no top-level heading so it can be embedded
+// in issues, PR comments, job summaries or files.
+func RenderMarkdown(findings []ValidationFinding) string {
+	var b strings.Builder
+
+	if len(findings) == 0 {
+		b.WriteString("No validation findings.\n")
+		return b.String()
+	}
+
+	sorted := slices.Clone(findings)
+	slices.SortStableFunc(sorted, func(x, y ValidationFinding) int {
+		return cmp.Or(
+			cmp.Compare(x.SubmoduleName, y.SubmoduleName),
+			cmp.Compare(x.ResourceType, y.ResourceType),
+			cmp.Compare(findingPath(x), findingPath(y)),
+			cmp.Compare(x.Name, y.Name),
+		)
+	})
+
+	required := 0
+	modules := 0
+	for i, finding := range sorted {
+		if finding.Required {
+			required++
+		}
+		if i == 0 || finding.SubmoduleName != sorted[i-1].SubmoduleName {
+			modules++
+		}
+	}
+
+	fmt.Fprintf(&b, "**%d** %s (%d required, %d optional) across %d %s.\n\n",
+		len(sorted), plural(len(sorted), "finding", "findings"),
+		required, len(sorted)-required,
+		modules, plural(modules, "module", "modules"))
+
+	for _, moduleGroup := range groupFindings(sorted, func(f ValidationFinding) string { return f.SubmoduleName }) {
+		moduleLabel := "root module"
+		if moduleGroup[0].SubmoduleName != "" {
+			moduleLabel = "module `" + moduleGroup[0].SubmoduleName + "`"
+		}
+
+		fmt.Fprintf(&b, "<details>\n<summary><b>%s</b> (%d %s)</summary>\n\n",
+			moduleLabel, len(moduleGroup), plural(len(moduleGroup), "finding", "findings"))
+
+		for _, typeGroup := range groupFindings(moduleGroup, func(f ValidationFinding) string { return f.ResourceType }) {
+			fmt.Fprintf(&b, "<details>\n<summary><code>%s</code> (%d %s)</summary>\n\n",
+				typeGroup[0].ResourceType, len(typeGroup), plural(len(typeGroup), "finding", "findings"))
+
+			b.WriteString("| Name | Path | Kind | Status | Entity |\n")
+			b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+			for _, finding := range typeGroup {
+				status := "optional"
+				if finding.Required {
+					status = "required"
+				}
+				if category := finding.category(); category != CategoryMissing {
+					status = string(category)
+				}
+
+				kind := "property"
+				if finding.IsBlock {
+					kind = "block"
+				}
+
+				entity := finding.kind().Label()
+				if finding.Check != "" {
+					entity += " in check `" + finding.Check + "`"
+				}
+
+				fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s |\n",
+					finding.Name, findingPath(finding), kind, status, entity)
+			}
+
+			b.WriteString("\n</details>\n\n")
+		}
+
+		b.WriteString("</details>\n\n")
+	}
+
+	return b.String()
+}
+
+func NewMarkdownReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		_, err := io.WriteString(w, RenderMarkdown(report.Findings))
+		return err
+	})
+}
+
+// groupFindings splits sorted findings into runs that share the same key.
+func groupFindings(findings []ValidationFinding, key func(ValidationFinding) string) [][]ValidationFinding {
+	var groups [][]ValidationFinding
+	for i, finding := range findings {
+		if i == 0 || key(finding) != key(findings[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], finding)
+	}
+	return groups
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,66 @@
+package diffy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownNoFindings(t *testing.T) {
+	if got := RenderMarkdown(nil); got != "No validation findings.\n" {
+		t.Fatalf("RenderMarkdown(nil) = %q", got)
+	}
+}
+
+func TestRenderMarkdownGroupsByModuleAndType(t *testing.T) {
+	findings := []ValidationFinding{
+		{ResourceType: "azurerm_subnet", Path: "root", Name: "delegation", IsBlock: true, SubmoduleName: "network"},
+		{ResourceType: "azurerm_linux_function_app", Path: "root.site_config", Name: "app_command_line"},
+		{ResourceType: "azurerm_linux_function_app", Path: "root", Name: "name", Required: true},
+		{ResourceType: "azurerm_client_config", Path: "root", Name: "timeouts", IsBlock: true, Kind: KindDataSource},
+	}
+
+	got := RenderMarkdown(findings)
+
+	if !strings.HasPrefix(got, "**4** findings (1 required, 3 optional) across 2 modules.") {
+		t.Fatalf("unexpected summary line: %q", strings.SplitN(got, "\n", 2)[0])
+	}
+
+	order := []string{
+		"<summary><b>root module</b> (3 findings)</summary>",
+		"<summary><code>azurerm_client_config</code> (1 finding)</summary>",
+		"| `timeouts` | `timeouts` | block | optional | data source |",
+		"<summary><code>azurerm_linux_function_app</code> (2 findings)</summary>",
+		"| `name` | `name` | property | required | resource |",
+		"| `app_command_line` | `site_config.app_command_line` | property | optional | resource |",
+		"<summary><b>module `network`</b> (1 finding)</summary>",
+		"| `delegation` | `delegation` | block | optional | resource |",
+	}
+
+	pos := 0
+	for _, want := range order {
+		idx := strings.Index(got[pos:], want)
+		if idx < 0 {
+			t.Fatalf("expected %q after offset %d in:\n%s", want, pos, got)
+		}
+		pos += idx + len(want)
+	}
+
+	if strings.Count(got, "<details>") != strings.Count(got, "</details>") {
+		t.Fatalf("unbalanced details tags:\n%s", got)
+	}
+}
+
+func TestMarkdownReporter(t *testing.T) {
+	var buf bytes.Buffer
+	report := NewReport(&SchemaValidatorOptions{}, nil, []ValidationFinding{
+		{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true},
+	})
+
+	if err := NewMarkdownReporter(&buf).Report(report); err != nil {
+		t.Fatalf("Report returned error: %v", err)
+	}
+	if buf.String() != RenderMarkdown(report.Findings) {
+		t.Fatalf("reporter output should match RenderMarkdown, got %q", buf.String())
+	}
+}
@@ -109,7 +109,7 @@ func TestParsedDataSource_Structure(t *testing.T) {
 		Type: "azurerm_resource_group",
 		Name: "existing",
 		Data: BlockData{
-			Properties:    map[string]bool{"name": true},
+			Properties:    map[string]ValueClass{"name": ValueConstant},
 			StaticBlocks:  make(map[string][]*ParsedBlock),
 			DynamicBlocks: make(map[string]*ParsedBlock),
 			IgnoreChanges: []string{},
@@ -124,7 +124,7 @@ func TestParsedDataSource_Structure(t *testing.T) {
 		t.Errorf("Name = %s, want existing", pds.Name)
 	}
 
-	if !pds.Data.Properties["name"] {
+	if _, ok := pds.Data.Properties["name"]; !ok {
 		t.Error("Data should have 'name' property")
 	}
 }
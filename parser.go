@@ -100,24 +100,8 @@ func (parser *DefaultHCLParser) parseProviderRequirementsFromBody(body *hclsynta
 		if blk.Type == "terraform" {
 			for _, innerBlk := range blk.Body.Blocks {
 				if innerBlk.Type == "required_providers" {
-					attrs, _ := innerBlk.Body.JustAttributes()
-					for name, attr := range attrs {
-						val, _ := attr.Expr.Value(nil)
-						if val.Type().IsObjectType() {
-							pc := ProviderConfig{}
-							if val.Type().HasAttribute("source") {
-								if sourceVal := val.GetAttr("source"); !sourceVal.IsNull() {
-									pc.Source = NormalizeSource(sourceVal.AsString())
-								}
-							}
-							if val.Type().HasAttribute("version") {
-								if versionVal := val.GetAttr("version"); !versionVal.IsNull() {
-									pc.Version = versionVal.AsString()
-								}
-							}
-							if pc.Source == "" {
-								pc.Source = NormalizeSource("hashicorp/" + name)
-							}
+					for name, attr := range innerBlk.Body.Attributes {
+						if pc, ok := parseRequiredProvider(name, attr.Expr); ok {
 							providers[name] = pc
 						}
 					}
@@ -128,46 +112,175 @@ func (parser *DefaultHCLParser) parseProviderRequirementsFromBody(body *hclsynta
 	return providers, nil
 }
 
+// parseRequiredProvider reads one required_providers entry. The object is read item by item because
+// configuration_aliases holds provider references that cannot be evaluated without a context.
+func parseRequiredProvider(name string, expr hclsyntax.Expression) (ProviderConfig, bool) {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return ProviderConfig{}, false
+	}
+
+	pc := ProviderConfig{}
+	for _, item := range object.Items {
+		key, ok := objectLiteralKey(item.KeyExpr)
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "source", "version":
+			value, ok := constantValue(item.ValueExpr)
+			if !ok || value.IsNull() || value.Type() != cty.String {
+				continue
+			}
+			if key == "source" {
+				pc.Source = NormalizeSource(value.AsString())
+			} else {
+				pc.Version = value.AsString()
+			}
+		case "configuration_aliases":
+			aliases, ok := item.ValueExpr.(*hclsyntax.TupleConsExpr)
+			if !ok {
+				continue
+			}
+			for _, aliasExpr := range aliases.Exprs {
+				traversal, diags := hcl.AbsTraversalForExpr(aliasExpr)
+				if diags.HasErrors() || len(traversal) != 2 {
+					continue
+				}
+				if attr, ok := traversal[1].(hcl.TraverseAttr); ok {
+					pc.ConfigurationAliases = append(pc.ConfigurationAliases, attr.Name)
+				}
+			}
+		}
+	}
+
+	if pc.Source == "" {
+		pc.Source = NormalizeSource("hashicorp/" + name)
+	}
+	return pc, true
+}
+
+// parseProviderReference reads the provider meta-argument of a resource or data source, such as
+// azurerm.secondary, or returns an empty string when it is not set.
+func parseProviderReference(body *hclsyntax.Body) string {
+	attr, ok := body.Attributes["provider"]
+	if !ok {
+		return ""
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+	if diags.HasErrors() {
+		return ""
+	}
+	reference, _ := traversalPath(traversal)
+	return reference
+}
+
 func (parser *DefaultHCLParser) parseMainFileFromBody(body *hclsyntax.Body) ([]ParsedResource, []ParsedDataSource, error) {
 	var resources []ParsedResource
 	var dataSources []ParsedDataSource
 
 	for _, blk := range body.Blocks {
-		if blk.Type == "resource" && len(blk.Labels) >= 2 {
+		if (blk.Type == "resource" || blk.Type == "ephemeral") && len(blk.Labels) >= 2 {
 			parsed := ParseSyntaxBody(blk.Body)
+			parsed.Data.Range = NewSourceRange(blk.Range())
 
 			res := ParsedResource{
-				Type: blk.Labels[0],
-				Name: blk.Labels[1],
-				Data: parsed.Data,
+				Type:     blk.Labels[0],
+				Name:     blk.Labels[1],
+				Kind:     EntityKind(blk.Type),
+				Provider: parseProviderReference(blk.Body),
+				Data:     parsed.Data,
 			}
 			resources = append(resources, res)
 		}
 
 		if blk.Type == "data" && len(blk.Labels) >= 2 {
-			parsed := ParseSyntaxBody(blk.Body)
+			dataSources = append(dataSources, parseDataSource(blk, ""))
+		}
 
-			ds := ParsedDataSource{
-				Type: blk.Labels[0],
-				Name: blk.Labels[1],
-				Data: parsed.Data,
+		if blk.Type == "provider" && len(blk.Labels) == 1 {
+			resources = append(resources, parseProviderBlock(blk))
+		}
+
+		if blk.Type == "check" && len(blk.Labels) == 1 {
+			for _, inner := range blk.Body.Blocks {
+				if inner.Type == "data" && len(inner.Labels) >= 2 {
+					dataSources = append(dataSources, parseDataSource(inner, blk.Labels[0]))
+				}
 			}
-			dataSources = append(dataSources, ds)
 		}
 	}
 	return resources, dataSources, nil
 }
 
+// parseProviderBlock reads a provider configuration block. The alias and version meta-arguments are
+// not part of the provider schema and are left out of the block data.
+func parseProviderBlock(blk *hclsyntax.Block) ParsedResource {
+	parsed := ParseSyntaxBody(blk.Body)
+	parsed.Data.Range = NewSourceRange(blk.Range())
+
+	alias := ""
+	if expr, ok := parsed.Data.Expressions["alias"]; ok {
+		if value, ok := constantValue(expr); ok && !value.IsNull() && value.Type() == cty.String {
+			alias = value.AsString()
+		}
+	}
+	for _, name := range []string{"alias", "version"} {
+		delete(parsed.Data.Properties, name)
+		delete(parsed.Data.Expressions, name)
+	}
+
+	return ParsedResource{
+		Type:     blk.Labels[0],
+		Name:     alias,
+		Kind:     KindProvider,
+		Provider: blk.Labels[0],
+		Data:     parsed.Data,
+	}
+}
+
+func parseDataSource(blk *hclsyntax.Block, check string) ParsedDataSource {
+	parsed := ParseSyntaxBody(blk.Body)
+	parsed.Data.Range = NewSourceRange(blk.Range())
+
+	return ParsedDataSource{
+		Type:     blk.Labels[0],
+		Name:     blk.Labels[1],
+		Provider: parseProviderReference(blk.Body),
+		Check:    check,
+		Data:     parsed.Data,
+	}
+}
+
+// ParseSyntaxBody parses a block body. The returned range covers the body only; callers that
+// hold the enclosing block replace it with the full block range.
 func ParseSyntaxBody(body *hclsyntax.Body) *ParsedBlock {
 	bd := NewBlockData()
+	bd.Range = NewSourceRange(body.SrcRange)
 	bd.ParseAttributes(body)
 	bd.ParseBlocks(body)
 	return &ParsedBlock{Data: bd}
 }
 
+// extractIgnoreChangesFromValue reads ignore_changes entries given as strings, either a single
+// string or a list of them. Each string is parsed like a traversal so it yields the same path.
 func extractIgnoreChangesFromValue(val cty.Value) []string {
+	if val.IsNull() || !val.IsWhollyKnown() {
+		return nil
+	}
+
+	if val.Type() == cty.String {
+		change := val.AsString()
+		if change == "all" {
+			return []string{"*all*"}
+		}
+		return []string{ignoreChangesStringPath(change)}
+	}
+
 	var changes []string
-	if val.Type().IsCollectionType() {
+	if val.Type().IsCollectionType() || val.Type().IsTupleType() {
 		for it := val.ElementIterator(); it.Next(); {
 			_, element := it.Element()
 			if element.Type() == cty.String {
@@ -175,13 +288,51 @@ func extractIgnoreChangesFromValue(val cty.Value) []string {
 				if change == "all" {
 					return []string{"*all*"}
 				}
-				changes = append(changes, change)
+				changes = append(changes, ignoreChangesStringPath(change))
 			}
 		}
 	}
 	return changes
 }
 
+// ignoreChangesStringPath renders a string ignore_changes entry the way a bare traversal would be,
+// so "site_config.0.always_on" becomes site_config[0].always_on.
+func ignoreChangesStringPath(change string) string {
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(change), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		traversal, diags = hclsyntax.ParseTraversalAbs([]byte(legacyIndexSteps(change)), "", hcl.InitialPos)
+	}
+	if diags.HasErrors() {
+		return change
+	}
+	if path, ok := traversalPath(traversal); ok {
+		return path
+	}
+	return change
+}
+
+// legacyIndexSteps turns numeric attribute steps such as the 0 in identity.0.type into index steps,
+// which is how Terraform reads them. Entries with quoted keys are returned unchanged.
+func legacyIndexSteps(change string) string {
+	if strings.ContainsAny(change, `"[`) {
+		return change
+	}
+
+	steps := strings.Split(change, ".")
+	var b strings.Builder
+	for i, step := range steps {
+		switch {
+		case i == 0:
+			b.WriteString(step)
+		case step != "" && strings.Trim(step, "0123456789") == "":
+			b.WriteString("[" + step + "]")
+		default:
+			b.WriteString("." + step)
+		}
+	}
+	return b.String()
+}
+
 func NormalizeSource(source string) string {
 	if strings.Contains(source, "/") && !strings.Contains(source, "registry.terraform.io/") {
 		return "registry.terraform.io/" + source
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -190,6 +191,32 @@ terraform {
 				}
 			},
 		},
+		{
+			name: "configuration aliases and custom local name",
+			tfContent: `
+terraform {
+  required_providers {
+    primary = {
+      source                = "hashicorp/azurerm"
+      version               = "~> 4.0"
+      configuration_aliases = [primary.hub, primary.spoke]
+    }
+  }
+}
+`,
+			wantErr:   false,
+			wantCount: 1,
+			checkResult: func(t *testing.T, providers map[string]ProviderConfig) {
+				want := ProviderConfig{
+					Source:               "registry.terraform.io/hashicorp/azurerm",
+					Version:              "~> 4.0",
+					ConfigurationAliases: []string{"hub", "spoke"},
+				}
+				if diff := cmp.Diff(want, providers["primary"]); diff != "" {
+					t.Errorf("provider mismatch (-want +got):\n%s", diff)
+				}
+			},
+		},
 		{
 			name: "no terraform block",
 			tfContent: `
@@ -331,6 +358,167 @@ data "azurerm_resource_group" "existing" {
 	}
 }
 
+func TestParseTerraformFilesRecordsRanges(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	content := `resource "azurerm_resource_group" "rg" {
+  name = "rg"
+}
+
+data "azurerm_client_config" "current" {}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	resources, dataSources, err := NewHCLParser().ParseTerraformFiles(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseTerraformFiles() error = %v", err)
+	}
+
+	want := SourceRange{Filename: tfFile, Start: SourcePos{Line: 1, Column: 1}, End: SourcePos{Line: 3, Column: 2}}
+	if resources[0].Data.Range != want {
+		t.Errorf("resource range = %+v, want %+v", resources[0].Data.Range, want)
+	}
+
+	if got := dataSources[0].Data.Range; got.Filename != tfFile || got.Start.Line != 5 || got.End.Line != 5 {
+		t.Errorf("unexpected data source range %+v", got)
+	}
+}
+
+func TestParseTerraformFilesRecordsProviderReference(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	content := `resource "azurerm_resource_group" "rg" {
+  provider = azurerm.secondary
+  name     = "rg"
+}
+
+data "azurerm_client_config" "current" {}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	resources, dataSources, err := NewHCLParser().ParseTerraformFiles(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseTerraformFiles() error = %v", err)
+	}
+
+	if resources[0].Provider != "azurerm.secondary" {
+		t.Errorf("resource provider = %q, want azurerm.secondary", resources[0].Provider)
+	}
+	if dataSources[0].Provider != "" {
+		t.Errorf("data source without provider meta-argument should have an empty provider, got %q", dataSources[0].Provider)
+	}
+}
+
+func TestParseTerraformFilesReadsCheckDataSources(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	content := `check "endpoint" {
+  data "azurerm_linux_web_app" "app" {
+    name                = "app"
+    resource_group_name = "rg"
+  }
+
+  assert {
+    condition     = data.azurerm_linux_web_app.app.enabled
+    error_message = "app is disabled"
+  }
+}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	_, dataSources, err := NewHCLParser().ParseTerraformFiles(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseTerraformFiles() error = %v", err)
+	}
+
+	if len(dataSources) != 1 {
+		t.Fatalf("expected one scoped data source, got %d", len(dataSources))
+	}
+	ds := dataSources[0]
+	if ds.Type != "azurerm_linux_web_app" || ds.Name != "app" || ds.Check != "endpoint" {
+		t.Fatalf("unexpected data source %+v", ds)
+	}
+	if _, ok := ds.Data.Properties["resource_group_name"]; !ok || ds.Data.Range.Start.Line != 2 {
+		t.Fatalf("scoped data source body was not parsed: %+v", ds.Data)
+	}
+}
+
+func TestParseTerraformFilesReadsEphemeralResources(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "main.tf")
+	content := `ephemeral "azurerm_key_vault_secret" "password" {
+  name         = "admin-password"
+  key_vault_id = var.key_vault_id
+}
+
+resource "azurerm_resource_group" "rg" {}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	resources, _, err := NewHCLParser().ParseTerraformFiles(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseTerraformFiles() error = %v", err)
+	}
+
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 entities, got %d", len(resources))
+	}
+	if resources[0].Kind != KindEphemeral || resources[0].Type != "azurerm_key_vault_secret" {
+		t.Errorf("unexpected ephemeral resource %+v", resources[0])
+	}
+	if resources[1].Kind != KindResource {
+		t.Errorf("resource blocks should have KindResource, got %q", resources[1].Kind)
+	}
+}
+
+func TestParseTerraformFilesReadsProviderBlocks(t *testing.T) {
+	tfFile := filepath.Join(t.TempDir(), "providers.tf")
+	content := `provider "azurerm" {
+  features {}
+}
+
+provider "azurerm" {
+  alias           = "connectivity"
+  subscription_id = var.connectivity_subscription_id
+  features {}
+}
+`
+	if err := os.WriteFile(tfFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	resources, _, err := NewHCLParser().ParseTerraformFiles(context.Background(), []string{tfFile})
+	if err != nil {
+		t.Fatalf("ParseTerraformFiles() error = %v", err)
+	}
+
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 provider blocks, got %d", len(resources))
+	}
+	for _, provider := range resources {
+		if provider.Kind != KindProvider || provider.Type != "azurerm" || provider.Provider != "azurerm" {
+			t.Fatalf("unexpected provider block %+v", provider)
+		}
+		if len(provider.Data.StaticBlocks["features"]) != 1 {
+			t.Fatalf("features block was not parsed: %+v", provider.Data)
+		}
+	}
+
+	aliased := resources[1]
+	if aliased.Name != "connectivity" {
+		t.Errorf("alias = %q, want connectivity", aliased.Name)
+	}
+	_, hasAlias := aliased.Data.Properties["alias"]
+	_, hasSubscription := aliased.Data.Properties["subscription_id"]
+	if hasAlias || !hasSubscription {
+		t.Errorf("alias should be dropped from the block data, got %v", aliased.Data.Properties)
+	}
+}
+
 func TestParseMainFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	tfFile := filepath.Join(tmpDir, "main.tf")
@@ -0,0 +1,129 @@
+// Package diffy provides failure policies that decide whether a validation run fails
+package diffy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type FailureMode string
+
+const (
+	FailNever      FailureMode = "never"
+	FailOnRequired FailureMode = "required"
+	FailOnError    FailureMode = "error"
+	FailOnAny      FailureMode = "any"
+	FailOnCount    FailureMode = "count"
+	FailOnNew      FailureMode = "new"
+)
+
+func ParseFailureMode(s string) (FailureMode, error) {
+	switch mode := FailureMode(s); mode {
+	case FailNever, FailOnRequired, FailOnError, FailOnAny, FailOnCount, FailOnNew:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown failure mode %q (expected never, required, error, any, count or new)", s)
+	}
+}
+
+// FailurePolicy decides whether a run fails. FailOnRequired fails on findings about required
+// attributes and blocks, FailOnError on error-level findings, which also cover configuration the
+// provider would reject. Threshold is only used by FailOnCount, where the run fails when the number
+// of findings exceeds it. Baseline is only used by FailOnNew, where the run fails on findings whose
+// fingerprint is not in the baseline.
+type FailurePolicy struct {
+	Mode      FailureMode
+	Threshold int
+	Baseline  []ValidationFinding
+}
+
+type PolicyResult struct {
+	Mode       FailureMode         `json:"mode"`
+	Failed     bool                `json:"failed"`
+	Reason     string              `json:"reason,omitempty"`
+	Violations []ValidationFinding `json:"violations"`
+}
+
+func (policy FailurePolicy) Evaluate(findings []ValidationFinding) PolicyResult {
+	result := PolicyResult{Mode: policy.Mode, Violations: []ValidationFinding{}}
+
+	switch policy.Mode {
+	case FailOnRequired:
+		for _, finding := range findings {
+			if finding.Required {
+				result.Violations = append(result.Violations, finding)
+			}
+		}
+		if len(result.Violations) > 0 {
+			result.Failed = true
+			result.Reason = fmt.Sprintf("%d required %s", len(result.Violations), plural(len(result.Violations), "finding", "findings"))
+		}
+	case FailOnError:
+		for _, finding := range findings {
+			if isErrorFinding(finding) {
+				result.Violations = append(result.Violations, finding)
+			}
+		}
+		if len(result.Violations) > 0 {
+			result.Failed = true
+			result.Reason = fmt.Sprintf("%d error-level %s", len(result.Violations), plural(len(result.Violations), "finding", "findings"))
+		}
+	case FailOnAny:
+		if len(findings) > 0 {
+			result.Violations = append(result.Violations, findings...)
+			result.Failed = true
+			result.Reason = fmt.Sprintf("%d %s", len(findings), plural(len(findings), "finding", "findings"))
+		}
+	case FailOnCount:
+		if len(findings) > policy.Threshold {
+			result.Violations = append(result.Violations, findings...)
+			result.Failed = true
+			result.Reason = fmt.Sprintf("%d findings exceed the threshold of %d", len(findings), policy.Threshold)
+		}
+	case FailOnNew:
+		known := make(map[string]struct{}, len(policy.Baseline))
+		for _, finding := range policy.Baseline {
+			known[FindingFingerprint(finding)] = struct{}{}
+		}
+		for _, finding := range findings {
+			if _, ok := known[FindingFingerprint(finding)]; !ok {
+				result.Violations = append(result.Violations, finding)
+			}
+		}
+		if len(result.Violations) > 0 {
+			result.Failed = true
+			result.Reason = fmt.Sprintf("%d new %s not in the baseline", len(result.Violations), plural(len(result.Violations), "finding", "findings"))
+		}
+	}
+
+	return result
+}
+
+// LoadBaseline reads the findings of a JSON report written by an earlier run.
+func LoadBaseline(path string) ([]ValidationFinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline %s: %w", path, err)
+	}
+
+	// Version 1 reports flagged data sources with is_data_source instead of kind.
+	var report struct {
+		Findings []struct {
+			ValidationFinding
+			IsDataSource bool `json:"is_data_source"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline %s: %w", path, err)
+	}
+
+	findings := make([]ValidationFinding, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		if finding.IsDataSource && finding.Kind == "" {
+			finding.Kind = KindDataSource
+		}
+		findings = append(findings, finding.ValidationFinding)
+	}
+	return findings, nil
+}
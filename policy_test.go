@@ -0,0 +1,164 @@
+package diffy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFailureMode(t *testing.T) {
+	for _, mode := range []FailureMode{FailNever, FailOnRequired, FailOnError, FailOnAny, FailOnCount, FailOnNew} {
+		got, err := ParseFailureMode(string(mode))
+		if err != nil || got != mode {
+			t.Errorf("ParseFailureMode(%q) = %q, %v", mode, got, err)
+		}
+	}
+
+	if _, err := ParseFailureMode("sometimes"); err == nil {
+		t.Errorf("expected error for unknown mode")
+	}
+}
+
+func TestFailurePolicyEvaluate(t *testing.T) {
+	required := ValidationFinding{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true}
+	optional := ValidationFinding{ResourceType: "azurerm_key_vault", Path: "root", Name: "tags"}
+	unknown := ValidationFinding{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku", Category: CategoryUnknown}
+
+	tests := []struct {
+		name           string
+		policy         FailurePolicy
+		findings       []ValidationFinding
+		wantFailed     bool
+		wantViolations int
+	}{
+		{name: "never", policy: FailurePolicy{Mode: FailNever}, findings: []ValidationFinding{required}},
+		{name: "required ignores optional", policy: FailurePolicy{Mode: FailOnRequired}, findings: []ValidationFinding{optional}},
+		{name: "required fails", policy: FailurePolicy{Mode: FailOnRequired}, findings: []ValidationFinding{required, optional}, wantFailed: true, wantViolations: 1},
+		{name: "required ignores optional errors", policy: FailurePolicy{Mode: FailOnRequired}, findings: []ValidationFinding{unknown}},
+		{name: "error ignores warnings", policy: FailurePolicy{Mode: FailOnError}, findings: []ValidationFinding{optional}},
+		{name: "error fails", policy: FailurePolicy{Mode: FailOnError}, findings: []ValidationFinding{required, unknown, optional}, wantFailed: true, wantViolations: 2},
+		{name: "any passes without findings", policy: FailurePolicy{Mode: FailOnAny}},
+		{name: "any fails", policy: FailurePolicy{Mode: FailOnAny}, findings: []ValidationFinding{optional}, wantFailed: true, wantViolations: 1},
+		{name: "count at threshold", policy: FailurePolicy{Mode: FailOnCount, Threshold: 2}, findings: []ValidationFinding{required, optional}},
+		{name: "count above threshold", policy: FailurePolicy{Mode: FailOnCount, Threshold: 1}, findings: []ValidationFinding{required, optional}, wantFailed: true, wantViolations: 2},
+		{name: "new within baseline", policy: FailurePolicy{Mode: FailOnNew, Baseline: []ValidationFinding{required, optional}}, findings: []ValidationFinding{required}},
+		{name: "new outside baseline", policy: FailurePolicy{Mode: FailOnNew, Baseline: []ValidationFinding{optional}}, findings: []ValidationFinding{required, optional}, wantFailed: true, wantViolations: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.policy.Evaluate(tt.findings)
+			if result.Failed != tt.wantFailed {
+				t.Errorf("Failed = %v, want %v (reason %q)", result.Failed, tt.wantFailed, result.Reason)
+			}
+			if len(result.Violations) != tt.wantViolations {
+				t.Errorf("got %d violations, want %d", len(result.Violations), tt.wantViolations)
+			}
+			if result.Failed && result.Reason == "" {
+				t.Errorf("failed results should carry a reason")
+			}
+		})
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	findings := []ValidationFinding{{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true}}
+	if err := NewReport(&SchemaValidatorOptions{}, nil, findings).WriteJSONFile(path); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline returned error: %v", err)
+	}
+	if len(baseline) != 1 || FindingFingerprint(baseline[0]) != FindingFingerprint(findings[0]) {
+		t.Fatalf("unexpected baseline: %+v", baseline)
+	}
+
+	if _, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("expected error for missing baseline")
+	}
+}
+
+func TestLoadBaselineReadsVersion1Reports(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	legacy := `{"schema_version": "1", "findings": [{"resource_type": "azurerm_client_config", "path": "root", "name": "timeouts", "is_block": true, "is_data_source": true}]}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline returned error: %v", err)
+	}
+
+	current := ValidationFinding{ResourceType: "azurerm_client_config", Path: "root", Name: "timeouts", IsBlock: true, Kind: KindDataSource}
+	if len(baseline) != 1 || FindingFingerprint(baseline[0]) != FindingFingerprint(current) {
+		t.Fatalf("legacy data source findings should match current ones, got %+v", baseline)
+	}
+}
+
+func TestValidateSchemaReturnsPolicyViolation(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "main.tf"), "# test")
+
+	parser := &validateStubParser{
+		providerSource: "registry.terraform.io/hashicorp/azurerm",
+		resources: []ParsedResource{
+			{Type: "azurerm_resource_group", Name: "rg", Data: NewBlockData()},
+		},
+	}
+	runner := &validateStubRunner{
+		schema: &TerraformSchema{
+			ProviderSchemas: map[string]*ProviderSchema{
+				parser.providerSource: {
+					ResourceSchemas: map[string]*ResourceSchema{
+						"azurerm_resource_group": {
+							Block: &SchemaBlock{
+								Attributes: map[string]*SchemaAttribute{
+									"location": {Required: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	findings, err := ValidateSchema(
+		WithTerraformRoot(root),
+		WithParser(parser),
+		WithTerraformRunner(runner),
+		WithFailurePolicy(FailurePolicy{Mode: FailOnRequired}),
+		func(opts *SchemaValidatorOptions) {
+			opts.Silent = true
+		},
+	)
+
+	var violation *PolicyViolationError
+	if !errors.As(err, &violation) {
+		t.Fatalf("expected PolicyViolationError, got %v", err)
+	}
+	if len(findings) != 1 || len(violation.Result.Violations) != 1 {
+		t.Fatalf("findings should be returned alongside the violation, got %d findings", len(findings))
+	}
+
+	report, err := ValidateSchemaReport(
+		WithTerraformRoot(root),
+		WithParser(parser),
+		WithTerraformRunner(runner),
+		WithFailurePolicy(FailurePolicy{Mode: FailOnCount, Threshold: 5}),
+		func(opts *SchemaValidatorOptions) {
+			opts.Silent = true
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error within threshold, got %v", err)
+	}
+	if report.Policy == nil || report.Policy.Failed || report.Policy.Mode != FailOnCount {
+		t.Fatalf("report should carry the policy result, got %+v", report.Policy)
+	}
+}
@@ -0,0 +1,169 @@
+// Package diffy provides machine-readable validation reports
+package diffy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// ReportSchemaVersion is bumped whenever a field of the JSON report changes meaning or is removed.
+// Version 2 replaced the is_data_source flags of findings and entities with kind, and made the
+// per-kind summary totals count validated entities instead of findings.
+const ReportSchemaVersion = "2"
+
+type Report struct {
+	SchemaVersion string              `json:"schema_version"`
+	Config        ReportConfig        `json:"config"`
+	Modules       []ModuleReport      `json:"modules"`
+	Findings      []ValidationFinding `json:"findings"`
+	Summary       ReportSummary       `json:"summary"`
+	Policy        *PolicyResult       `json:"policy,omitempty"`
+}
+
+type ReportConfig struct {
+	TerraformRoot       string   `json:"terraform_root"`
+	ExcludedResources   []string `json:"excluded_resources"`
+	ExcludedDataSources []string `json:"excluded_data_sources"`
+	CreateGitHubIssue   bool     `json:"create_github_issue"`
+}
+
+type ModuleReport struct {
+	Name     string         `json:"name"`
+	Path     string         `json:"path"`
+	Root     bool           `json:"root"`
+	Findings int            `json:"findings"`
+	Entities []EntityReport `json:"entities"`
+	Coverage Coverage       `json:"coverage"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// EntityReport describes one validated resource or data source. Its findings are not deduplicated
+// across resources of the same type, unlike Report.Findings. Values counts how its properties are
+// set, telling attributes plumbed through inputs apart from hard-coded ones, and Coverage how much
+// of its schema it configures.
+type EntityReport struct {
+	Type     string              `json:"type"`
+	Name     string              `json:"name"`
+	Kind     EntityKind          `json:"kind"`
+	Check    string              `json:"check,omitempty"`
+	Location SourceRange         `json:"location,omitzero"`
+	Values   ValueCounts         `json:"values"`
+	Coverage Coverage            `json:"coverage"`
+	Findings []ValidationFinding `json:"findings"`
+}
+
+// ReportSummary totals the run. Resources, DataSources, Ephemeral and Providers count the validated
+// entities, not their findings.
+type ReportSummary struct {
+	Modules     int                     `json:"modules"`
+	Findings    int                     `json:"findings"`
+	Errors      int                     `json:"errors"`
+	Required    int                     `json:"required"`
+	Optional    int                     `json:"optional"`
+	Resources   int                     `json:"resources"`
+	DataSources int                     `json:"data_sources"`
+	Ephemeral   int                     `json:"ephemeral_resources"`
+	Providers   int                     `json:"providers"`
+	Categories  map[FindingCategory]int `json:"categories"`
+	Values      ValueCounts             `json:"values"`
+	Coverage    Coverage                `json:"coverage"`
+}
+
+func NewReport(opts *SchemaValidatorOptions, modules []ModuleReport, findings []ValidationFinding) *Report {
+	report := &Report{
+		SchemaVersion: ReportSchemaVersion,
+		Config: ReportConfig{
+			TerraformRoot:       opts.TerraformRoot,
+			ExcludedResources:   nonNil(opts.ExcludedResources),
+			ExcludedDataSources: nonNil(opts.ExcludedDataSources),
+			CreateGitHubIssue:   opts.CreateGitHubIssue,
+		},
+		Modules:  slices.Clone(nonNil(modules)),
+		Findings: slices.Clone(nonNil(findings)),
+	}
+
+	for i := range report.Findings {
+		report.Findings[i].Category = report.Findings[i].category()
+		report.Findings[i].Kind = report.Findings[i].kind()
+	}
+
+	report.Summary.Categories = make(map[FindingCategory]int)
+
+	perModule := make(map[string]int)
+	for _, finding := range report.Findings {
+		perModule[finding.SubmoduleName]++
+		report.Summary.Categories[finding.Category]++
+
+		if isErrorFinding(finding) {
+			report.Summary.Errors++
+		}
+		if finding.Required {
+			report.Summary.Required++
+		} else {
+			report.Summary.Optional++
+		}
+	}
+
+	for i := range report.Modules {
+		report.Modules[i].Findings = perModule[report.Modules[i].Name]
+		report.Modules[i].Coverage = Coverage{}
+		for _, entity := range report.Modules[i].Entities {
+			switch entity.Kind.orDefault() {
+			case KindDataSource:
+				report.Summary.DataSources++
+			case KindEphemeral:
+				report.Summary.Ephemeral++
+			case KindProvider:
+				report.Summary.Providers++
+			default:
+				report.Summary.Resources++
+			}
+			report.Summary.Values.merge(entity.Values)
+			report.Modules[i].Coverage.merge(entity.Coverage)
+		}
+		report.Modules[i].Coverage.updatePercent()
+		report.Summary.Coverage.merge(report.Modules[i].Coverage)
+	}
+	report.Summary.Coverage.updatePercent()
+
+	report.Summary.Modules = len(report.Modules)
+	report.Summary.Findings = len(report.Findings)
+
+	return report
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return nil
+}
+
+func (r *Report) WriteJSONFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON report %s: %w", path, err)
+	}
+
+	if err := r.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write JSON report %s: %w", path, err)
+	}
+	return nil
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,212 @@
+package diffy
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewReportComputesSummary(t *testing.T) {
+	opts := &SchemaValidatorOptions{
+		TerraformRoot:     "./module",
+		ExcludedResources: []string{"azurerm_role_assignment"},
+	}
+	modules := []ModuleReport{
+		{Path: "/abs/module", Root: true, Entities: []EntityReport{
+			{Type: "azurerm_virtual_network", Name: "vnet", Values: ValueCounts{Constant: 2, Reference: 1}, Coverage: Coverage{Configured: 3, Total: 4}},
+		}},
+		{Name: "network", Path: "/abs/module/modules/network", Entities: []EntityReport{
+			{Type: "azurerm_subnet", Name: "subnet", Values: ValueCounts{Null: 1, Reference: 3, Complex: 1}, Coverage: Coverage{Configured: 1, Total: 5}},
+			{Type: "azurerm_client_config", Name: "current", Kind: KindDataSource},
+			{Type: "azurerm_subnet", Name: "existing", Kind: KindDataSource},
+			{Type: "azurerm", Kind: KindProvider},
+		}},
+	}
+	findings := []ValidationFinding{
+		{ResourceType: "azurerm_virtual_network", Path: "root", Name: "location", Required: true},
+		{ResourceType: "azurerm_subnet", Path: "root", Name: "delegation", IsBlock: true, SubmoduleName: "network"},
+		{ResourceType: "azurerm_client_config", Path: "root", Name: "timeouts", IsBlock: true, Kind: KindDataSource, SubmoduleName: "network"},
+	}
+
+	report := NewReport(opts, modules, findings)
+
+	want := ReportSummary{
+		Modules:     2,
+		Findings:    3,
+		Errors:      1,
+		Required:    1,
+		Optional:    2,
+		Resources:   2,
+		DataSources: 2,
+		Providers:   1,
+		Categories:  map[FindingCategory]int{CategoryMissing: 3},
+		Values:      ValueCounts{Null: 1, Constant: 2, Reference: 4, Complex: 1},
+		Coverage:    Coverage{Configured: 4, Total: 9, Percent: 44.4},
+	}
+	if diff := cmp.Diff(want, report.Summary); diff != "" {
+		t.Fatalf("summary mismatch (-want +got):\n%s", diff)
+	}
+
+	if report.Modules[0].Findings != 1 || report.Modules[1].Findings != 2 {
+		t.Fatalf("unexpected per-module finding counts: %+v", report.Modules)
+	}
+	if report.Modules[0].Coverage.Percent != 75 || report.Modules[1].Coverage.Percent != 20 {
+		t.Fatalf("unexpected per-module coverage: %+v", report.Modules)
+	}
+	if modules[1].Findings != 0 {
+		t.Fatalf("NewReport should not mutate the caller's modules")
+	}
+	if report.Config.ExcludedDataSources == nil {
+		t.Fatalf("excluded data sources should encode as an empty list")
+	}
+}
+
+func TestReportWriteJSONUsesStableFieldNames(t *testing.T) {
+	report := NewReport(&SchemaValidatorOptions{TerraformRoot: "root"}, []ModuleReport{{Path: "root", Root: true}}, []ValidationFinding{
+		{ResourceType: "azurerm_key_vault", Path: "root.network_acls", Name: "bypass", Required: true},
+	})
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+
+	if decoded["schema_version"] != ReportSchemaVersion {
+		t.Fatalf("unexpected schema_version %v", decoded["schema_version"])
+	}
+
+	finding := decoded["findings"].([]any)[0].(map[string]any)
+	want := map[string]any{
+		"resource_type": "azurerm_key_vault",
+		"path":          "root.network_acls",
+		"name":          "bypass",
+		"category":      "missing",
+		"required":      true,
+		"is_block":      false,
+		"kind":          "resource",
+	}
+	if diff := cmp.Diff(want, finding); diff != "" {
+		t.Fatalf("finding JSON mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewReportKeepsLocalsAndOutputKinds(t *testing.T) {
+	report := NewReport(&SchemaValidatorOptions{}, nil, []ValidationFinding{
+		{ResourceType: "locals", Path: "root", Name: "subnet_id", Category: CategoryFunction, Kind: KindLocals},
+		{ResourceType: "output.vnet", Path: "root", Name: "value", Category: CategoryFunction, Kind: KindOutput},
+	})
+
+	if report.Findings[0].Kind != KindLocals || report.Findings[1].Kind != KindOutput {
+		t.Fatalf("locals and output findings should keep their kind, got %+v", report.Findings)
+	}
+	if report.Summary.Resources != 0 {
+		t.Fatalf("locals and output findings should not count as resources, got %+v", report.Summary)
+	}
+}
+
+func TestReportWriteJSONEmptyFindings(t *testing.T) {
+	report := NewReport(&SchemaValidatorOptions{}, nil, nil)
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"findings": []`) || !strings.Contains(buf.String(), `"modules": []`) {
+		t.Fatalf("empty collections should encode as arrays, got %s", buf.String())
+	}
+}
+
+func TestReportWriteJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	report := NewReport(&SchemaValidatorOptions{}, nil, nil)
+
+	if err := report.WriteJSONFile(path); err != nil {
+		t.Fatalf("WriteJSONFile returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("report file is not valid JSON: %v", err)
+	}
+	if decoded.SchemaVersion != ReportSchemaVersion {
+		t.Fatalf("unexpected schema version %q", decoded.SchemaVersion)
+	}
+}
+
+func TestReportWriteJSONFileInvalidPath(t *testing.T) {
+	report := NewReport(&SchemaValidatorOptions{}, nil, nil)
+	if err := report.WriteJSONFile(filepath.Join(t.TempDir(), "missing", "report.json")); err == nil {
+		t.Fatalf("expected error for missing directory")
+	}
+}
+
+func TestValidateSchemaReportWritesJSON(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "main.tf"), "# test")
+
+	parser := &validateStubParser{
+		providerSource: "registry.terraform.io/hashicorp/azurerm",
+		resources: []ParsedResource{
+			{Type: "azurerm_resource_group", Name: "rg", Data: NewBlockData()},
+		},
+	}
+	runner := &validateStubRunner{
+		schema: &TerraformSchema{
+			ProviderSchemas: map[string]*ProviderSchema{
+				parser.providerSource: {
+					ResourceSchemas: map[string]*ResourceSchema{
+						"azurerm_resource_group": {
+							Block: &SchemaBlock{
+								Attributes: map[string]*SchemaAttribute{
+									"location": {Required: true},
+								},
+							},
+						},
+					},
+					DataSourceSchemas: map[string]*ResourceSchema{},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	report, err := ValidateSchemaReport(
+		WithTerraformRoot(root),
+		WithParser(parser),
+		WithTerraformRunner(runner),
+		WithJSONReportWriter(&buf),
+		func(opts *SchemaValidatorOptions) {
+			opts.Silent = true
+		},
+	)
+	if err != nil {
+		t.Fatalf("ValidateSchemaReport returned error: %v", err)
+	}
+
+	if report.Summary.Findings != 1 || report.Summary.Modules != 1 || !report.Modules[0].Root {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("written report is not valid JSON: %v", err)
+	}
+	if diff := cmp.Diff(report, &decoded); diff != "" {
+		t.Fatalf("written report differs from returned report (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,53 @@
+// Package diffy provides reporters that render validation results
+package diffy
+
+import (
+	"fmt"
+	"io"
+)
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(report *Report) error
+
+func (f ReporterFunc) Report(report *Report) error {
+	return f(report)
+}
+
+// NewTextReporter writes one FormatFinding line per finding. It is the default when no reporter is configured.
+func NewTextReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		if len(report.Findings) == 0 {
+			_, err := fmt.Fprintln(w, "No validation findings.")
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "Found %d issues:\n", len(report.Findings)); err != nil {
+			return err
+		}
+
+		for _, finding := range report.Findings {
+			if _, err := fmt.Fprintln(w, FormatFinding(finding)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func NewJSONReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		return report.WriteJSON(w)
+	})
+}
+
+func NewSARIFReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		return WriteSARIF(w, report.Findings)
+	})
+}
+
+func NewJUnitReporter(w io.Writer) Reporter {
+	return ReporterFunc(func(report *Report) error {
+		return WriteJUnit(w, report)
+	})
+}
@@ -0,0 +1,119 @@
+package diffy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextReporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []ValidationFinding
+		want     []string
+	}{
+		{
+			name: "no findings",
+			want: []string{"No validation findings."},
+		},
+		{
+			name: "findings",
+			findings: []ValidationFinding{
+				{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true},
+			},
+			want: []string{"Found 1 issues:", "azurerm_key_vault: missing required property sku_name in root (resource)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			report := NewReport(&SchemaValidatorOptions{}, nil, tt.findings)
+
+			if err := NewTextReporter(&buf).Report(report); err != nil {
+				t.Fatalf("Report returned error: %v", err)
+			}
+
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %q", len(got), len(tt.want), buf.String())
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchemaRunsEveryReporter(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "main.tf"), "# test")
+
+	parser := &validateStubParser{providerSource: "registry.terraform.io/hashicorp/azurerm"}
+	runner := &validateStubRunner{
+		schema: &TerraformSchema{
+			ProviderSchemas: map[string]*ProviderSchema{
+				parser.providerSource: {
+					ResourceSchemas:   map[string]*ResourceSchema{},
+					DataSourceSchemas: map[string]*ResourceSchema{},
+				},
+			},
+		},
+	}
+
+	var text, jsonOut bytes.Buffer
+	var called int
+	_, err := ValidateSchema(
+		WithTerraformRoot(root),
+		WithParser(parser),
+		WithTerraformRunner(runner),
+		WithReporter(NewTextReporter(&text), NewJSONReporter(&jsonOut)),
+		WithReporter(ReporterFunc(func(*Report) error {
+			called++
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("ValidateSchema returned error: %v", err)
+	}
+
+	if !strings.Contains(text.String(), "No validation findings.") {
+		t.Fatalf("text reporter output missing, got %q", text.String())
+	}
+	var report Report
+	if err := json.Unmarshal(jsonOut.Bytes(), &report); err != nil {
+		t.Fatalf("JSON reporter output invalid: %v", err)
+	}
+	if called != 1 {
+		t.Fatalf("custom reporter should run once, ran %d times", called)
+	}
+}
+
+func TestValidateSchemaReturnsReporterError(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "main.tf"), "# test")
+
+	parser := &validateStubParser{providerSource: "registry.terraform.io/hashicorp/azurerm"}
+	runner := &validateStubRunner{
+		schema: &TerraformSchema{ProviderSchemas: map[string]*ProviderSchema{}},
+	}
+
+	boom := errors.New("disk full")
+	_, err := ValidateSchema(
+		WithTerraformRoot(root),
+		WithParser(parser),
+		WithTerraformRunner(runner),
+		WithReporter(ReporterFunc(func(*Report) error { return boom })),
+		func(opts *SchemaValidatorOptions) {
+			opts.Logger = &stubLogger{}
+		},
+	)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected reporter error, got %v", err)
+	}
+}
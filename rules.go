@@ -0,0 +1,172 @@
+// Package diffy provides the catalogue of finding classes shared by all output formats
+package diffy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+type FindingRule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       string
+}
+
+var (
+	RuleMissingRequiredProperty = FindingRule{
+		ID:          "DIFFY001",
+		Name:        "MissingRequiredProperty",
+		Description: "A required attribute from the provider schema is not configured.",
+		Level:       "error",
+	}
+	RuleMissingOptionalProperty = FindingRule{
+		ID:          "DIFFY002",
+		Name:        "MissingOptionalProperty",
+		Description: "An optional attribute from the provider schema is not configured.",
+		Level:       "warning",
+	}
+	RuleMissingRequiredBlock = FindingRule{
+		ID:          "DIFFY003",
+		Name:        "MissingRequiredBlock",
+		Description: "A required nested block from the provider schema is not configured.",
+		Level:       "error",
+	}
+	RuleMissingOptionalBlock = FindingRule{
+		ID:          "DIFFY004",
+		Name:        "MissingOptionalBlock",
+		Description: "An optional nested block from the provider schema is not configured.",
+		Level:       "warning",
+	}
+	RuleUnknownProperty = FindingRule{
+		ID:          "DIFFY005",
+		Name:        "UnknownProperty",
+		Description: "A configured attribute does not exist in the provider schema.",
+		Level:       "error",
+	}
+	RuleUnknownBlock = FindingRule{
+		ID:          "DIFFY006",
+		Name:        "UnknownBlock",
+		Description: "A configured block does not exist in the provider schema.",
+		Level:       "error",
+	}
+	RuleDeprecatedProperty = FindingRule{
+		ID:          "DIFFY007",
+		Name:        "DeprecatedProperty",
+		Description: "A configured attribute is deprecated in the provider schema.",
+		Level:       "warning",
+	}
+	RuleDeprecatedBlock = FindingRule{
+		ID:          "DIFFY008",
+		Name:        "DeprecatedBlock",
+		Description: "A configured block is deprecated in the provider schema.",
+		Level:       "warning",
+	}
+	RuleBlockCardinality = FindingRule{
+		ID:          "DIFFY009",
+		Name:        "BlockCardinality",
+		Description: "A nested block is declared more or fewer times than the provider schema allows.",
+		Level:       "error",
+	}
+	RuleTypeMismatch = FindingRule{
+		ID:          "DIFFY010",
+		Name:        "TypeMismatch",
+		Description: "A literal attribute value cannot be converted to the type in the provider schema.",
+		Level:       "error",
+	}
+	RuleHardcodedSensitiveValue = FindingRule{
+		ID:          "DIFFY011",
+		Name:        "HardcodedSensitiveValue",
+		Description: "A sensitive attribute is assigned a literal string instead of a variable or data source.",
+		Level:       "error",
+	}
+	RuleStaleIgnoreChanges = FindingRule{
+		ID:          "DIFFY012",
+		Name:        "StaleIgnoreChanges",
+		Description: "An ignore_changes entry refers to nothing in the schema or to a computed-only attribute.",
+		Level:       "warning",
+	}
+	RuleInvalidFunctionCall = FindingRule{
+		ID:          "DIFFY013",
+		Name:        "InvalidFunctionCall",
+		Description: "A provider-defined function is not defined by the provider or is called with the wrong number of arguments.",
+		Level:       "error",
+	}
+	RuleRequiredPropertyNull = FindingRule{
+		ID:          "DIFFY014",
+		Name:        "RequiredPropertyNull",
+		Description: "A required property is set, but always to the literal null.",
+		Level:       "error",
+	}
+	RuleUndeclaredProviderAlias = FindingRule{
+		ID:          "DIFFY015",
+		Name:        "UndeclaredProviderAlias",
+		Description: "The provider meta-argument refers to an alias that no configuration_aliases entry or provider block declares.",
+		Level:       "error",
+	}
+)
+
+// FindingRules lists every class of finding diffy can report, in a stable order.
+var FindingRules = []FindingRule{
+	RuleMissingRequiredProperty,
+	RuleMissingOptionalProperty,
+	RuleMissingRequiredBlock,
+	RuleMissingOptionalBlock,
+	RuleUnknownProperty,
+	RuleUnknownBlock,
+	RuleDeprecatedProperty,
+	RuleDeprecatedBlock,
+	RuleBlockCardinality,
+	RuleTypeMismatch,
+	RuleHardcodedSensitiveValue,
+	RuleStaleIgnoreChanges,
+	RuleInvalidFunctionCall,
+	RuleRequiredPropertyNull,
+	RuleUndeclaredProviderAlias,
+}
+
+func RuleForFinding(finding ValidationFinding) FindingRule {
+	switch {
+	case finding.category() == CategoryUnknown && finding.IsBlock:
+		return RuleUnknownBlock
+	case finding.category() == CategoryUnknown:
+		return RuleUnknownProperty
+	case finding.category() == CategoryDeprecated && finding.IsBlock:
+		return RuleDeprecatedBlock
+	case finding.category() == CategoryDeprecated:
+		return RuleDeprecatedProperty
+	case finding.category() == CategoryCardinality:
+		return RuleBlockCardinality
+	case finding.category() == CategoryType:
+		return RuleTypeMismatch
+	case finding.category() == CategorySensitive:
+		return RuleHardcodedSensitiveValue
+	case finding.category() == CategoryIgnoreChanges:
+		return RuleStaleIgnoreChanges
+	case finding.category() == CategoryFunction:
+		return RuleInvalidFunctionCall
+	case finding.category() == CategoryNull:
+		return RuleRequiredPropertyNull
+	case finding.category() == CategoryProvider:
+		return RuleUndeclaredProviderAlias
+	case finding.IsBlock && finding.Required:
+		return RuleMissingRequiredBlock
+	case finding.IsBlock:
+		return RuleMissingOptionalBlock
+	case finding.Required:
+		return RuleMissingRequiredProperty
+	default:
+		return RuleMissingOptionalProperty
+	}
+}
+
+// isErrorFinding reports whether the finding breaks a plan or apply, as opposed to a completeness warning.
+func isErrorFinding(finding ValidationFinding) bool {
+	return RuleForFinding(finding).Level == "error"
+}
+
+// FindingFingerprint returns a stable identifier for a finding that survives reordering between runs.
+func FindingFingerprint(finding ValidationFinding) string {
+	sum := sha256.Sum256([]byte(findingKey(finding)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,121 @@
+package diffy
+
+import (
+	"testing"
+)
+
+func TestRuleForFinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding ValidationFinding
+		want    FindingRule
+	}{
+		{
+			name:    "required property",
+			finding: ValidationFinding{Required: true},
+			want:    RuleMissingRequiredProperty,
+		},
+		{
+			name:    "optional property",
+			finding: ValidationFinding{},
+			want:    RuleMissingOptionalProperty,
+		},
+		{
+			name:    "required block",
+			finding: ValidationFinding{Required: true, IsBlock: true},
+			want:    RuleMissingRequiredBlock,
+		},
+		{
+			name:    "optional block",
+			finding: ValidationFinding{IsBlock: true},
+			want:    RuleMissingOptionalBlock,
+		},
+		{
+			name:    "unknown property",
+			finding: ValidationFinding{Category: CategoryUnknown},
+			want:    RuleUnknownProperty,
+		},
+		{
+			name:    "unknown block",
+			finding: ValidationFinding{Category: CategoryUnknown, IsBlock: true},
+			want:    RuleUnknownBlock,
+		},
+		{
+			name:    "deprecated property",
+			finding: ValidationFinding{Category: CategoryDeprecated},
+			want:    RuleDeprecatedProperty,
+		},
+		{
+			name:    "deprecated block",
+			finding: ValidationFinding{Category: CategoryDeprecated, IsBlock: true},
+			want:    RuleDeprecatedBlock,
+		},
+		{
+			name:    "block cardinality",
+			finding: ValidationFinding{Category: CategoryCardinality, Required: true, IsBlock: true},
+			want:    RuleBlockCardinality,
+		},
+		{
+			name:    "type mismatch",
+			finding: ValidationFinding{Category: CategoryType, Required: true},
+			want:    RuleTypeMismatch,
+		},
+		{
+			name:    "hard-coded sensitive value",
+			finding: ValidationFinding{Category: CategorySensitive},
+			want:    RuleHardcodedSensitiveValue,
+		},
+		{
+			name:    "stale ignore_changes entry",
+			finding: ValidationFinding{Category: CategoryIgnoreChanges},
+			want:    RuleStaleIgnoreChanges,
+		},
+		{
+			name:    "invalid provider function call",
+			finding: ValidationFinding{Category: CategoryFunction},
+			want:    RuleInvalidFunctionCall,
+		},
+		{
+			name:    "required property set to null",
+			finding: ValidationFinding{Category: CategoryNull, Required: true},
+			want:    RuleRequiredPropertyNull,
+		},
+		{
+			name:    "undeclared provider alias",
+			finding: ValidationFinding{Category: CategoryProvider},
+			want:    RuleUndeclaredProviderAlias,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RuleForFinding(tt.finding); got != tt.want {
+				t.Errorf("RuleForFinding() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingRulesHaveUniqueIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range FindingRules {
+		if seen[rule.ID] {
+			t.Fatalf("duplicate rule id %s", rule.ID)
+		}
+		seen[rule.ID] = true
+	}
+}
+
+func TestFindingFingerprint(t *testing.T) {
+	a := ValidationFinding{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true}
+	b := a
+	c := a
+	c.SubmoduleName = "vault"
+
+	if FindingFingerprint(a) != FindingFingerprint(b) {
+		t.Fatalf("identical findings should share a fingerprint")
+	}
+	if FindingFingerprint(a) == FindingFingerprint(c) {
+		t.Fatalf("findings in different submodules should have different fingerprints")
+	}
+}
@@ -0,0 +1,179 @@
+// Package diffy provides SARIF export of validation findings
+package diffy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI   = "https://github.com/cloudnationhq/az-cn-go-diffy"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log with one rule per finding class.
+func WriteSARIF(w io.Writer, findings []ValidationFinding) error {
+	rules := make([]sarifRule, len(FindingRules))
+	ruleIndex := make(map[string]int, len(FindingRules))
+	for i, rule := range FindingRules {
+		rules[i] = sarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+		}
+		ruleIndex[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		rule := RuleForFinding(finding)
+		results = append(results, sarifResult{
+			RuleID:    rule.ID,
+			RuleIndex: ruleIndex[rule.ID],
+			Level:     rule.Level,
+			Message:   sarifMessage{Text: FormatFinding(finding)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocationFor(finding.Location),
+				LogicalLocations: []sarifLogicalLocation{sarifLogicalLocationFor(finding)},
+			}},
+			PartialFingerprints: map[string]string{
+				"diffyFinding/v1": FindingFingerprint(finding),
+			},
+		})
+	}
+
+	sarif := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "diffy",
+				InformationURI: sarifToolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sarif); err != nil {
+		return fmt.Errorf("failed to encode SARIF log: %w", err)
+	}
+	return nil
+}
+
+// sarifPhysicalLocationFor points at the block enclosing the finding, or returns nil when the
+// parser did not record where it was declared.
+func sarifPhysicalLocationFor(location SourceRange) *sarifPhysicalLocation {
+	if location.Filename == "" || location.Start.Line == 0 {
+		return nil
+	}
+	return &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: location.RelativeFilename()},
+		Region: sarifRegion{
+			StartLine:   location.Start.Line,
+			StartColumn: location.Start.Column,
+			EndLine:     location.End.Line,
+			EndColumn:   location.End.Column,
+		},
+	}
+}
+
+func sarifLogicalLocationFor(finding ValidationFinding) sarifLogicalLocation {
+	qualified := finding.ResourceType
+	if cleanPath := strings.TrimPrefix(strings.TrimPrefix(finding.Path, "root"), "."); cleanPath != "" {
+		qualified += "." + cleanPath
+	}
+	qualified += "." + finding.Name
+	if finding.Check != "" {
+		qualified = "check." + finding.Check + "." + qualified
+	}
+	if finding.SubmoduleName != "" {
+		qualified = "module." + finding.SubmoduleName + "." + qualified
+	}
+
+	kind := finding.kind().Label()
+
+	return sarifLogicalLocation{
+		Name:               finding.Name,
+		FullyQualifiedName: qualified,
+		Kind:               kind,
+	}
+}
@@ -0,0 +1,120 @@
+package diffy
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	findings := []ValidationFinding{
+		{ResourceType: "azurerm_linux_function_app", Path: "root.site_config", Name: "app_command_line"},
+		{ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name", Required: true, SubmoduleName: "vault"},
+		{ResourceType: "azurerm_client_config", Path: "root", Name: "timeouts", IsBlock: true, Kind: KindDataSource},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, findings); err != nil {
+		t.Fatalf("WriteSARIF returned error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(FindingRules) {
+		t.Fatalf("expected %d rules, got %d", len(FindingRules), len(run.Tool.Driver.Rules))
+	}
+
+	if len(run.Results) != len(findings) {
+		t.Fatalf("expected %d results, got %d", len(findings), len(run.Results))
+	}
+
+	for _, result := range run.Results {
+		if run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Fatalf("ruleIndex %d does not point at rule %s", result.RuleIndex, result.RuleID)
+		}
+	}
+
+	gotRules := []string{run.Results[0].RuleID, run.Results[1].RuleID, run.Results[2].RuleID}
+	wantRules := []string{RuleMissingOptionalProperty.ID, RuleMissingRequiredProperty.ID, RuleMissingOptionalBlock.ID}
+	if diff := cmp.Diff(wantRules, gotRules); diff != "" {
+		t.Fatalf("rule ids mismatch (-want +got):\n%s", diff)
+	}
+
+	if run.Results[1].Level != "error" || run.Results[0].Level != "warning" {
+		t.Fatalf("unexpected levels: %s, %s", run.Results[0].Level, run.Results[1].Level)
+	}
+
+	wantLocations := []sarifLogicalLocation{
+		{Name: "app_command_line", FullyQualifiedName: "azurerm_linux_function_app.site_config.app_command_line", Kind: "resource"},
+		{Name: "sku_name", FullyQualifiedName: "module.vault.azurerm_key_vault.sku_name", Kind: "resource"},
+		{Name: "timeouts", FullyQualifiedName: "azurerm_client_config.timeouts", Kind: "data source"},
+	}
+	for i, want := range wantLocations {
+		if diff := cmp.Diff(want, run.Results[i].Locations[0].LogicalLocations[0]); diff != "" {
+			t.Errorf("result %d logical location mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestWriteSARIFPhysicalLocation(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+
+	finding := ValidationFinding{
+		ResourceType: "azurerm_linux_function_app",
+		Path:         "root",
+		Name:         "site_config",
+		IsBlock:      true,
+		Location: SourceRange{
+			Filename: filepath.Join(wd, "examples", "module", "main.tf"),
+			Start:    SourcePos{Line: 1, Column: 1},
+			End:      SourcePos{Line: 40, Column: 2},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, []ValidationFinding{finding, {ResourceType: "azurerm_key_vault", Path: "root", Name: "sku_name"}}); err != nil {
+		t.Fatalf("WriteSARIF returned error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	want := &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: "examples/module/main.tf"},
+		Region:           sarifRegion{StartLine: 1, StartColumn: 1, EndLine: 40, EndColumn: 2},
+	}
+	if diff := cmp.Diff(want, log.Runs[0].Results[0].Locations[0].PhysicalLocation); diff != "" {
+		t.Fatalf("physical location mismatch (-want +got):\n%s", diff)
+	}
+
+	if log.Runs[0].Results[1].Locations[0].PhysicalLocation != nil {
+		t.Fatalf("findings without a location should not get a physical location")
+	}
+}
+
+func TestWriteSARIFNoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, nil); err != nil {
+		t.Fatalf("WriteSARIF returned error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Fatalf("an empty run should still emit a results array, got %s", buf.String())
+	}
+}
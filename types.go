@@ -3,6 +3,15 @@ package diffy
 
 import (
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ParseError struct {
@@ -56,13 +65,41 @@ func (e *GitHubError) Unwrap() error {
 	return e.Err
 }
 
+type PolicyViolationError struct {
+	Result PolicyResult
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("failure policy %s violated: %s", e.Result.Mode, e.Result.Reason)
+}
+
 type TerraformSchema struct {
 	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
 }
 
 type ProviderSchema struct {
-	ResourceSchemas   map[string]*ResourceSchema `json:"resource_schemas"`
-	DataSourceSchemas map[string]*ResourceSchema `json:"data_source_schemas"`
+	ResourceSchemas          map[string]*ResourceSchema    `json:"resource_schemas"`
+	DataSourceSchemas        map[string]*ResourceSchema    `json:"data_source_schemas"`
+	EphemeralResourceSchemas map[string]*ResourceSchema    `json:"ephemeral_resource_schemas"`
+	Provider                 *ResourceSchema               `json:"provider"`
+	Functions                map[string]*FunctionSignature `json:"functions"`
+}
+
+// Provider blocks share the single provider configuration schema regardless of type.
+func (schema *ProviderSchema) EntitySchema(kind EntityKind, entityType string) (*ResourceSchema, bool) {
+	var schemas map[string]*ResourceSchema
+	switch kind {
+	case KindProvider:
+		return schema.Provider, schema.Provider != nil
+	case KindDataSource:
+		schemas = schema.DataSourceSchemas
+	case KindEphemeral:
+		schemas = schema.EphemeralResourceSchemas
+	default:
+		schemas = schema.ResourceSchemas
+	}
+	resourceSchema, ok := schemas[entityType]
+	return resourceSchema, ok
 }
 
 type ResourceSchema struct {
@@ -70,57 +107,284 @@ type ResourceSchema struct {
 }
 
 type SchemaBlock struct {
-	Attributes map[string]*SchemaAttribute `json:"attributes"`
-	BlockTypes map[string]*SchemaBlockType `json:"block_types"`
+	Attributes      map[string]*SchemaAttribute `json:"attributes"`
+	BlockTypes      map[string]*SchemaBlockType `json:"block_types"`
+	Description     string                      `json:"description"`
+	DescriptionKind string                      `json:"description_kind"`
+	Deprecated      bool                        `json:"deprecated"`
 }
 
 type SchemaAttribute struct {
-	Required   bool `json:"required"`
-	Optional   bool `json:"optional"`
-	Computed   bool `json:"computed"`
-	Deprecated bool `json:"deprecated"`
+	Type            cty.Type          `json:"type"`
+	NestedType      *SchemaNestedType `json:"nested_type"`
+	Required        bool              `json:"required"`
+	Optional        bool              `json:"optional"`
+	Computed        bool              `json:"computed"`
+	Deprecated      bool              `json:"deprecated"`
+	Sensitive       bool              `json:"sensitive"`
+	Description     string            `json:"description"`
+	DescriptionKind string            `json:"description_kind"`
+}
+
+type SchemaNestedType struct {
+	Attributes  map[string]*SchemaAttribute `json:"attributes"`
+	NestingMode string                      `json:"nesting_mode"`
+	MinItems    int                         `json:"min_items"`
+	MaxItems    int                         `json:"max_items"`
 }
 
 type SchemaBlockType struct {
-	Nesting    string       `json:"nesting"`
+	Nesting    string       `json:"nesting_mode"`
 	MinItems   int          `json:"min_items"`
 	MaxItems   int          `json:"max_items"`
 	Block      *SchemaBlock `json:"block"`
 	Deprecated bool         `json:"deprecated"`
 }
 
+// MaxBlocks is the largest number of blocks the type accepts, or 0 when it is unbounded. Single and
+// group nesting modes accept at most one block regardless of max_items.
+func (blockType *SchemaBlockType) MaxBlocks() int {
+	switch blockType.Nesting {
+	case "single", "group":
+		return 1
+	}
+	return blockType.MaxItems
+}
+
+// IsDeprecated reports whether the block type is deprecated. Terraform marks deprecation on the
+// nested block rather than on the block type itself.
+func (blockType *SchemaBlockType) IsDeprecated() bool {
+	return blockType.Deprecated || (blockType.Block != nil && blockType.Block.Deprecated)
+}
+
+type EntityKind string
+
+const (
+	KindResource   EntityKind = "resource"
+	KindDataSource EntityKind = "data"
+	KindEphemeral  EntityKind = "ephemeral"
+	KindProvider   EntityKind = "provider"
+	// KindLocals and KindOutput only mark findings about expressions in locals and output blocks,
+	// such as calls to provider-defined functions. They are never validated as entities.
+	KindLocals EntityKind = "locals"
+	KindOutput EntityKind = "output"
+)
+
+func (kind EntityKind) Label() string {
+	switch kind {
+	case KindDataSource:
+		return "data source"
+	case KindEphemeral:
+		return "ephemeral resource"
+	case KindProvider:
+		return "provider"
+	case KindLocals:
+		return "locals"
+	case KindOutput:
+		return "output"
+	default:
+		return "resource"
+	}
+}
+
+func (kind EntityKind) Address(entityType, name string) string {
+	switch kind {
+	case KindProvider:
+		if name == "" {
+			return "provider." + entityType
+		}
+		return "provider." + entityType + "." + name
+	case KindDataSource, KindEphemeral:
+		return string(kind) + "." + entityType + "." + name
+	default:
+		return entityType + "." + name
+	}
+}
+
+func (kind EntityKind) orDefault() EntityKind {
+	if kind == "" {
+		return KindResource
+	}
+	return kind
+}
+
+type FindingCategory string
+
+const (
+	CategoryMissing       FindingCategory = "missing"
+	CategoryUnknown       FindingCategory = "unknown"
+	CategoryDeprecated    FindingCategory = "deprecated"
+	CategoryCardinality   FindingCategory = "cardinality"
+	CategoryType          FindingCategory = "type"
+	CategorySensitive     FindingCategory = "sensitive"
+	CategoryIgnoreChanges FindingCategory = "ignore_changes"
+	CategoryFunction      FindingCategory = "function"
+	CategoryNull          FindingCategory = "null"
+	CategoryProvider      FindingCategory = "provider"
+)
+
 type ValidationFinding struct {
-	ResourceType  string
-	Path          string
-	Name          string
-	Required      bool
-	IsBlock       bool
-	IsDataSource  bool
-	SubmoduleName string
+	ResourceType  string          `json:"resource_type"`
+	Path          string          `json:"path"`
+	Name          string          `json:"name"`
+	Category      FindingCategory `json:"category"`
+	Required      bool            `json:"required"`
+	IsBlock       bool            `json:"is_block"`
+	Kind          EntityKind      `json:"kind"`
+	SubmoduleName string          `json:"submodule,omitempty"`
+	Check         string          `json:"check,omitempty"`
+	Location      SourceRange     `json:"location,omitzero"`
+	Suggestion    string          `json:"suggestion,omitempty"`
+	Detail        string          `json:"detail,omitempty"`
+}
+
+func (finding ValidationFinding) kind() EntityKind {
+	return finding.Kind.orDefault()
+}
+
+func (finding ValidationFinding) category() FindingCategory {
+	if finding.Category == "" {
+		return CategoryMissing
+	}
+	return finding.Category
+}
+
+type SourceRange struct {
+	Filename string    `json:"file"`
+	Start    SourcePos `json:"start"`
+	End      SourcePos `json:"end"`
+}
+
+type SourcePos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func NewSourceRange(r hcl.Range) SourceRange {
+	return SourceRange{
+		Filename: r.Filename,
+		Start:    SourcePos{Line: r.Start.Line, Column: r.Start.Column},
+		End:      SourcePos{Line: r.End.Line, Column: r.End.Column},
+	}
+}
+
+func (r SourceRange) IsZero() bool {
+	return r.Filename == "" && r.Start.Line == 0
+}
+
+func (r SourceRange) RelativeFilename() string {
+	if r.Filename == "" {
+		return ""
+	}
+	filename := r.Filename
+	if filepath.IsAbs(filename) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+				filename = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filename)
 }
 
 type ProviderConfig struct {
-	Source  string
-	Version string
+	Source               string
+	Version              string
+	ConfigurationAliases []string
+	Aliases              []string
+}
+
+func (cfg ProviderConfig) declaresAlias(alias string) bool {
+	return slices.Contains(cfg.ConfigurationAliases, alias) || slices.Contains(cfg.Aliases, alias)
 }
 
 type ParsedResource struct {
-	Type string
-	Name string
-	Data BlockData
+	Type     string
+	Name     string
+	Kind     EntityKind
+	Provider string
+	Data     BlockData
 }
 
 type ParsedDataSource struct {
-	Type string
-	Name string
-	Data BlockData
+	Type     string
+	Name     string
+	Provider string
+	Check    string
+	Data     BlockData
+}
+
+type ValueClass string
+
+const (
+	ValueNull      ValueClass = "null"
+	ValueConstant  ValueClass = "constant"
+	ValueReference ValueClass = "reference"
+	ValueComplex   ValueClass = "complex"
+)
+
+type ValueCounts struct {
+	Null      int `json:"null"`
+	Constant  int `json:"constant"`
+	Reference int `json:"reference"`
+	Complex   int `json:"complex"`
+}
+
+func (counts *ValueCounts) add(class ValueClass) {
+	switch class {
+	case ValueNull:
+		counts.Null++
+	case ValueConstant:
+		counts.Constant++
+	case ValueReference:
+		counts.Reference++
+	default:
+		counts.Complex++
+	}
+}
+
+func (counts *ValueCounts) merge(other ValueCounts) {
+	counts.Null += other.Null
+	counts.Constant += other.Constant
+	counts.Reference += other.Reference
+	counts.Complex += other.Complex
+}
+
+type Coverage struct {
+	Configured int     `json:"configured"`
+	Total      int     `json:"total"`
+	Percent    float64 `json:"percent"`
+}
+
+func (coverage *Coverage) add(configured bool) {
+	coverage.Total++
+	if configured {
+		coverage.Configured++
+	}
+	coverage.updatePercent()
+}
+
+func (coverage *Coverage) merge(other Coverage) {
+	coverage.Configured += other.Configured
+	coverage.Total += other.Total
+	coverage.updatePercent()
+}
+
+func (coverage *Coverage) updatePercent() {
+	if coverage.Total == 0 {
+		coverage.Percent = 100
+		return
+	}
+	coverage.Percent = math.Round(float64(coverage.Configured)*1000/float64(coverage.Total)) / 10
 }
 
 type BlockData struct {
-	Properties    map[string]bool
+	Properties    map[string]ValueClass
+	Expressions   map[string]hclsyntax.Expression
 	StaticBlocks  map[string][]*ParsedBlock
 	DynamicBlocks map[string]*ParsedBlock
 	IgnoreChanges []string
+	Range         SourceRange
 }
 
 type ParsedBlock struct {
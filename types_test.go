@@ -1,8 +1,14 @@
 package diffy
 
 import (
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestParseError(t *testing.T) {
@@ -160,3 +166,96 @@ func TestGitHubError_Unwrap(t *testing.T) {
 		t.Errorf("Unwrap() = %v, want %v", unwrapped, innerErr)
 	}
 }
+
+func TestNewSourceRange(t *testing.T) {
+	r := NewSourceRange(hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 3, Column: 1, Byte: 20},
+		End:      hcl.Pos{Line: 9, Column: 2, Byte: 140},
+	})
+
+	want := SourceRange{Filename: "main.tf", Start: SourcePos{Line: 3, Column: 1}, End: SourcePos{Line: 9, Column: 2}}
+	if r != want {
+		t.Errorf("NewSourceRange() = %+v, want %+v", r, want)
+	}
+	if r.IsZero() {
+		t.Errorf("IsZero() should be false for a populated range")
+	}
+	if !(SourceRange{}).IsZero() {
+		t.Errorf("IsZero() should be true for an empty range")
+	}
+}
+
+func TestSourceRangeRelativeFilename(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{name: "empty", filename: "", want: ""},
+		{name: "relative", filename: "modules/network/main.tf", want: "modules/network/main.tf"},
+		{name: "inside working directory", filename: filepath.Join(wd, "examples", "module", "main.tf"), want: "examples/module/main.tf"},
+		{name: "outside working directory", filename: "/elsewhere/main.tf", want: "/elsewhere/main.tf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (SourceRange{Filename: tt.filename}).RelativeFilename(); got != tt.want {
+				t.Errorf("RelativeFilename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyViolationError(t *testing.T) {
+	err := &PolicyViolationError{Result: PolicyResult{Mode: FailOnRequired, Failed: true, Reason: "2 required findings"}}
+
+	want := "failure policy required violated: 2 required findings"
+	if got := err.Error(); got != want {
+		t.Errorf("PolicyViolationError.Error() = %v, want %v", got, want)
+	}
+}
+
+func TestSchemaBlockTypeDecodesTerraformJSON(t *testing.T) {
+	var blockType SchemaBlockType
+	data := `{"nesting_mode": "single", "max_items": 0, "block": {"deprecated": true, "description": "Use sku instead."}}`
+	if err := json.Unmarshal([]byte(data), &blockType); err != nil {
+		t.Fatalf("failed to decode block type: %v", err)
+	}
+
+	if blockType.Nesting != "single" || blockType.MaxBlocks() != 1 {
+		t.Errorf("single nesting should allow one block, got nesting %q max %d", blockType.Nesting, blockType.MaxBlocks())
+	}
+	if !blockType.IsDeprecated() || blockType.Block.Description != "Use sku instead." {
+		t.Errorf("deprecation should be read from the nested block, got %+v", blockType.Block)
+	}
+}
+
+func TestSchemaAttributeDecodesType(t *testing.T) {
+	var attribute SchemaAttribute
+	if err := json.Unmarshal([]byte(`{"type": ["set", "string"], "optional": true}`), &attribute); err != nil {
+		t.Fatalf("failed to decode attribute: %v", err)
+	}
+	if !attribute.Type.Equals(cty.Set(cty.String)) {
+		t.Fatalf("expected set of string, got %s", attribute.Type.FriendlyName())
+	}
+}
+
+func TestSchemaAttributeDecodesNestedType(t *testing.T) {
+	var attribute SchemaAttribute
+	data := `{"nested_type": {"nesting_mode": "list", "attributes": {"action": {"type": "string", "required": true}}}, "optional": true}`
+	if err := json.Unmarshal([]byte(data), &attribute); err != nil {
+		t.Fatalf("failed to decode attribute: %v", err)
+	}
+	if attribute.NestedType == nil || attribute.NestedType.NestingMode != "list" {
+		t.Fatalf("expected list nested type, got %+v", attribute.NestedType)
+	}
+	if action := attribute.NestedType.Attributes["action"]; action == nil || !action.Required || !action.Type.Equals(cty.String) {
+		t.Fatalf("unexpected nested attribute %+v", action)
+	}
+}
@@ -11,7 +11,8 @@ import (
 )
 
 type DefaultSchemaValidator struct {
-	logger Logger
+	logger   Logger
+	entities []EntityReport
 }
 
 func NewSchemaValidator(logger Logger) *DefaultSchemaValidator {
@@ -26,7 +27,7 @@ func (validator *DefaultSchemaValidator) ValidateResources(
 	providers map[string]ProviderConfig,
 	dir, submoduleName string,
 ) []ValidationFinding {
-	return validator.validateEntities(resources, schema, providers, dir, submoduleName, false)
+	return validator.validateEntities(resources, schema, providers, dir, submoduleName)
 }
 
 func (validator *DefaultSchemaValidator) ValidateDataSources(
@@ -35,7 +36,38 @@ func (validator *DefaultSchemaValidator) ValidateDataSources(
 	providers map[string]ProviderConfig,
 	dir, submoduleName string,
 ) []ValidationFinding {
-	return validator.validateEntities(dataSources, schema, providers, dir, submoduleName, true)
+	return validator.validateEntities(dataSources, schema, providers, dir, submoduleName)
+}
+
+// A type prefix without a matching local name falls back to the provider whose source has that type,
+// for modules that use a custom local name.
+func resolveProvider(entityType, providerReference string, providers map[string]ProviderConfig) (ProviderConfig, bool) {
+	if providerReference != "" {
+		localName, _, _ := strings.Cut(providerReference, ".")
+		cfg, ok := providers[localName]
+		return cfg, ok
+	}
+
+	prefix := strings.SplitN(entityType, "_", 2)[0]
+	if cfg, ok := providers[prefix]; ok {
+		return cfg, true
+	}
+
+	for _, localName := range slices.Sorted(maps.Keys(providers)) {
+		if cfg := providers[localName]; cfg.Source[strings.LastIndex(cfg.Source, "/")+1:] == prefix {
+			return cfg, true
+		}
+	}
+	return ProviderConfig{}, false
+}
+
+type parsedEntity struct {
+	Type     string
+	Name     string
+	Kind     EntityKind
+	Provider string
+	Check    string
+	Data     BlockData
 }
 
 func (validator *DefaultSchemaValidator) validateEntities(
@@ -43,43 +75,29 @@ func (validator *DefaultSchemaValidator) validateEntities(
 	schema TerraformSchema,
 	providers map[string]ProviderConfig,
 	dir, submoduleName string,
-	isDataSource bool,
 ) []ValidationFinding {
 	var findings []ValidationFinding
 
-	var entityList []struct {
-		Type string
-		Name string
-		Data BlockData
-	}
+	var entityList []parsedEntity
 
 	switch e := entities.(type) {
 	case []ParsedResource:
 		for _, r := range e {
-			entityList = append(entityList, struct {
-				Type string
-				Name string
-				Data BlockData
-			}{r.Type, r.Name, r.Data})
+			entityList = append(entityList, parsedEntity{Type: r.Type, Name: r.Name, Kind: r.Kind.orDefault(), Provider: r.Provider, Data: r.Data})
 		}
 	case []ParsedDataSource:
 		for _, ds := range e {
-			entityList = append(entityList, struct {
-				Type string
-				Name string
-				Data BlockData
-			}{ds.Type, ds.Name, ds.Data})
+			entityList = append(entityList, parsedEntity{Type: ds.Type, Name: ds.Name, Kind: KindDataSource, Provider: ds.Provider, Check: ds.Check, Data: ds.Data})
 		}
 	default:
 		return findings
 	}
 
 	for _, entity := range entityList {
-		provName := strings.SplitN(entity.Type, "_", 2)[0]
-		cfg, ok := providers[provName]
+		cfg, ok := resolveProvider(entity.Type, entity.Provider, providers)
 		if !ok {
 			validator.logger.Logf("No provider config for %s type %s in %s",
-				map[bool]string{true: "data source", false: "resource"}[isDataSource],
+				entity.Kind.Label(),
 				entity.Type, dir)
 			continue
 		}
@@ -90,28 +108,38 @@ func (validator *DefaultSchemaValidator) validateEntities(
 			continue
 		}
 
-		var resSchema *ResourceSchema
-		var schemaExists bool
-		if isDataSource {
-			resSchema, schemaExists = pSchema.DataSourceSchemas[entity.Type]
-		} else {
-			resSchema, schemaExists = pSchema.ResourceSchemas[entity.Type]
-		}
-
+		resSchema, schemaExists := pSchema.EntitySchema(entity.Kind, entity.Type)
 		if !schemaExists {
-			entityType := map[bool]string{true: "data source", false: "resource"}[isDataSource]
 			validator.logger.Logf("No %s schema found for %s in provider %s (dir=%s)",
-				entityType, entity.Type, cfg.Source, dir)
+				entity.Kind.Label(), entity.Type, cfg.Source, dir)
 			continue
 		}
 
 		var localFindings []ValidationFinding
-		entity.Data.Validate(entity.Type, "root", resSchema.Block, entity.Data.IgnoreChanges, &localFindings)
+		entityFindings := []ValidationFinding{}
+
+		if _, alias, hasAlias := strings.Cut(entity.Provider, "."); hasAlias && !cfg.declaresAlias(alias) {
+			localFindings = append(localFindings, ValidationFinding{
+				ResourceType: entity.Type,
+				Path:         "root",
+				Name:         "provider",
+				Category:     CategoryProvider,
+				Location:     entity.Data.Range,
+				Detail: fmt.Sprintf("refers to %s, which no configuration_aliases entry or provider block alias declares",
+					entity.Provider),
+			})
+		}
+
+		coverage := entity.Data.Validate(entity.Type, "root", resSchema.Block, entity.Data.IgnoreChanges, &localFindings)
 
 		for i := range localFindings {
 			shouldExclude := false
 			for _, ignored := range entity.Data.IgnoreChanges {
-				if strings.EqualFold(ignored, localFindings[i].Name) {
+				if category := localFindings[i].Category; category != CategoryMissing && category != CategoryNull {
+					// ignore_changes hides what is left out or null, not mistakes in what is configured.
+					break
+				}
+				if ignored != "*all*" && ignoreChangesMatch(ignored, findingPath(localFindings[i])) {
 					shouldExclude = true
 					break
 				}
@@ -119,48 +147,77 @@ func (validator *DefaultSchemaValidator) validateEntities(
 
 			if !shouldExclude {
 				localFindings[i].SubmoduleName = submoduleName
-				localFindings[i].IsDataSource = isDataSource
+				localFindings[i].Kind = entity.Kind
+				localFindings[i].Check = entity.Check
 				findings = append(findings, localFindings[i])
+				entityFindings = append(entityFindings, localFindings[i])
 			}
 		}
+
+		validator.entities = append(validator.entities, EntityReport{
+			Type:     entity.Type,
+			Name:     entity.Name,
+			Kind:     entity.Kind,
+			Check:    entity.Check,
+			Location: entity.Data.Range,
+			Values:   entity.Data.CountValues(),
+			Coverage: coverage,
+			Findings: entityFindings,
+		})
 	}
 
 	return findings
 }
 
+func (validator *DefaultSchemaValidator) Entities() []EntityReport {
+	return validator.entities
+}
+
 func ValidateTerraformSchema(logger Logger, dir, submoduleName string, parser HCLParser, runner TerraformRunner) ([]ValidationFinding, error) {
 	return ValidateTerraformSchemaWithOptions(logger, dir, submoduleName, parser, runner, nil, nil)
 }
 
 func ValidateTerraformSchemaWithOptions(logger Logger, dir, submoduleName string, parser HCLParser, runner TerraformRunner, excludedResources, excludedDataSources []string) ([]ValidationFinding, error) {
+	findings, _, err := validateModule(logger, dir, submoduleName, parser, runner, excludedResources, excludedDataSources)
+	return findings, err
+}
+
+func validateModule(logger Logger, dir, submoduleName string, parser HCLParser, runner TerraformRunner, excludedResources, excludedDataSources []string) ([]ValidationFinding, []EntityReport, error) {
 	ctx := context.Background()
 
 	terraformFiles, err := walkTerraformFiles(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover Terraform files in %s: %w", dir, err)
+		return nil, nil, fmt.Errorf("failed to discover Terraform files in %s: %w", dir, err)
 	}
 
 	providers := make(map[string]ProviderConfig)
 	for _, tfFile := range terraformFiles {
 		parsedProviders, err := parser.ParseProviderRequirements(ctx, tfFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse provider config in %s: %w", tfFile, err)
+			return nil, nil, fmt.Errorf("failed to parse provider config in %s: %w", tfFile, err)
 		}
 		maps.Copy(providers, parsedProviders)
 	}
 
 	if err := runner.Init(ctx, dir); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	tfSchema, err := runner.GetSchema(ctx, dir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	resources, dataSources, err := parser.ParseTerraformFiles(ctx, terraformFiles)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Terraform resources in %s: %w", dir, err)
+		return nil, nil, fmt.Errorf("failed to parse Terraform resources in %s: %w", dir, err)
+	}
+
+	for _, resource := range resources {
+		if cfg, ok := providers[resource.Type]; ok && resource.Kind == KindProvider && resource.Name != "" {
+			cfg.Aliases = append(cfg.Aliases, resource.Name)
+			providers[resource.Type] = cfg
+		}
 	}
 
 	resources = filterResources(resources, excludedResources)
@@ -171,7 +228,16 @@ func ValidateTerraformSchemaWithOptions(logger Logger, dir, submoduleName string
 	findings = append(findings, validator.ValidateResources(resources, *tfSchema, providers, dir, submoduleName)...)
 	findings = append(findings, validator.ValidateDataSources(dataSources, *tfSchema, providers, dir, submoduleName)...)
 
-	return findings, nil
+	if functionParser, ok := parser.(FunctionCallParser); ok {
+		calls, err := functionParser.ParseFunctionCalls(ctx, terraformFiles)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse function calls in %s: %w", dir, err)
+		}
+		calls = filterFunctionCalls(calls, excludedResources, excludedDataSources)
+		findings = append(findings, validateFunctionCalls(logger, calls, *tfSchema, providers, validator.Entities(), dir, submoduleName)...)
+	}
+
+	return findings, validator.Entities(), nil
 }
 
 func filterResources(resources []ParsedResource, excluded []string) []ParsedResource {
@@ -202,6 +268,24 @@ func filterDataSources(dataSources []ParsedDataSource, excluded []string) []Pars
 	return filtered
 }
 
+func filterFunctionCalls(calls []FunctionCall, excludedResources, excludedDataSources []string) []FunctionCall {
+	if len(excludedResources) == 0 && len(excludedDataSources) == 0 {
+		return calls
+	}
+
+	var filtered []FunctionCall
+	for _, call := range calls {
+		excluded := excludedResources
+		if call.Kind == KindDataSource {
+			excluded = excludedDataSources
+		}
+		if !slices.Contains(excluded, call.Owner) {
+			filtered = append(filtered, call)
+		}
+	}
+	return filtered
+}
+
 func walkTerraformFiles(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -227,14 +311,7 @@ func DeduplicateFindings(findings []ValidationFinding) []ValidationFinding {
 	result := make([]ValidationFinding, 0, len(findings))
 
 	for _, finding := range findings {
-		key := fmt.Sprintf("%s|%s|%s|%v|%v|%s",
-			finding.ResourceType,
-			finding.Path,
-			finding.Name,
-			finding.IsBlock,
-			finding.IsDataSource,
-			finding.SubmoduleName,
-		)
+		key := findingKey(finding)
 
 		if _, exists := seen[key]; !exists {
 			seen[key] = struct{}{}
@@ -245,6 +322,33 @@ func DeduplicateFindings(findings []ValidationFinding) []ValidationFinding {
 	return result
 }
 
+func findingPath(finding ValidationFinding) string {
+	return relativePath(finding.Path, finding.Name)
+}
+
+// The category is only appended for non-missing findings so fingerprints of missing findings stay
+// stable across releases.
+func findingKey(finding ValidationFinding) string {
+	key := fmt.Sprintf("%s|%s|%s|%v|%v|%s",
+		finding.ResourceType,
+		finding.Path,
+		finding.Name,
+		finding.IsBlock,
+		finding.kind() == KindDataSource,
+		finding.SubmoduleName,
+	)
+	if category := finding.category(); category != CategoryMissing {
+		key += "|" + string(category)
+	}
+	if finding.kind() == KindEphemeral {
+		key += "|" + string(KindEphemeral)
+	}
+	if finding.Check != "" {
+		key += "|check." + finding.Check
+	}
+	return key
+}
+
 func FormatFinding(finding ValidationFinding) string {
 	cleanPath := strings.ReplaceAll(finding.Path, "root.", "")
 
@@ -262,16 +366,59 @@ func FormatFinding(finding ValidationFinding) string {
 		blockOrProp = "block"
 	}
 
-	entityType := "resource"
-	if finding.IsDataSource {
-		entityType = "data source"
-	}
+	entityType := finding.kind().Label()
 
 	place := cleanPath
+	if finding.Check != "" {
+		place = place + " in check " + finding.Check
+	}
 	if finding.SubmoduleName != "" {
 		place = place + " in submodule " + finding.SubmoduleName
 	}
 
-	return fmt.Sprintf("%s: missing %s %s %s in %s (%s)",
-		finding.ResourceType, requiredOptional, blockOrProp, finding.Name, place, entityType)
+	switch finding.category() {
+	case CategoryUnknown:
+		message := fmt.Sprintf("%s: unknown %s %s in %s (%s)",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType)
+		if finding.Suggestion != "" {
+			message += fmt.Sprintf(", did you mean %s?", finding.Suggestion)
+		}
+		return message
+	case CategoryDeprecated:
+		message := fmt.Sprintf("%s: deprecated %s %s in %s (%s)",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType)
+		if finding.Detail != "" {
+			message += ": " + finding.Detail
+		}
+		return message
+	case CategoryCardinality:
+		return fmt.Sprintf("%s: invalid number of %s blocks in %s (%s): %s",
+			finding.ResourceType, finding.Name, place, entityType, finding.Detail)
+	case CategoryType:
+		return fmt.Sprintf("%s: invalid value for %s %s in %s (%s): %s",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType, finding.Detail)
+	case CategorySensitive:
+		return fmt.Sprintf("%s: hard-coded value for sensitive %s %s in %s (%s)",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType)
+	case CategoryNull:
+		return fmt.Sprintf("%s: required %s %s in %s (%s) is always null",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType)
+	case CategoryProvider:
+		return fmt.Sprintf("%s: %s %s in %s (%s) %s",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType, finding.Detail)
+	case CategoryFunction:
+		// The resource type already names locals and outputs, so their kind is left out.
+		if kind := finding.kind(); kind == KindLocals || kind == KindOutput {
+			return fmt.Sprintf("%s: %s %s in %s %s",
+				finding.ResourceType, blockOrProp, finding.Name, place, finding.Detail)
+		}
+		return fmt.Sprintf("%s: %s %s in %s (%s) %s",
+			finding.ResourceType, blockOrProp, finding.Name, place, entityType, finding.Detail)
+	case CategoryIgnoreChanges:
+		return fmt.Sprintf("%s: ignore_changes entry %s in %s (%s) %s",
+			finding.ResourceType, finding.Name, place, entityType, finding.Detail)
+	default:
+		return fmt.Sprintf("%s: missing %s %s %s in %s (%s)",
+			finding.ResourceType, requiredOptional, blockOrProp, finding.Name, place, entityType)
+	}
 }
@@ -4,7 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestDeduplicateFindings(t *testing.T) {
@@ -125,7 +131,7 @@ func TestFormatFinding(t *testing.T) {
 				Name:         "existing",
 				Path:         "name",
 				Required:     true,
-				IsDataSource: true,
+				Kind:         KindDataSource,
 			},
 			wantContains: []string{"azurerm_virtual_network", "existing", "name"},
 		},
@@ -137,11 +143,91 @@ func TestFormatFinding(t *testing.T) {
 				Path:          "root.block",
 				Required:      false,
 				IsBlock:       true,
-				IsDataSource:  true,
+				Kind:          KindDataSource,
 				SubmoduleName: "network",
 			},
 			wantContains: []string{"azurerm_storage_account", "block", "submodule network", "data source"},
 		},
+		{
+			name: "check data source",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_linux_web_app",
+				Path:         "root",
+				Name:         "location",
+				Kind:         KindDataSource,
+				Check:        "endpoint",
+			},
+			wantContains: []string{"root in check endpoint", "data source"},
+		},
+		{
+			name: "unknown property with suggestion",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_storage_account",
+				Path:         "root",
+				Name:         "pubic_network_access_enabled",
+				Category:     CategoryUnknown,
+				Suggestion:   "public_network_access_enabled",
+			},
+			wantContains: []string{"unknown property pubic_network_access_enabled", "did you mean public_network_access_enabled?"},
+		},
+		{
+			name: "deprecated block with detail",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_kubernetes_cluster",
+				Path:         "root",
+				Name:         "addon_profile",
+				Category:     CategoryDeprecated,
+				IsBlock:      true,
+				Detail:       "use the top-level addon blocks instead",
+			},
+			wantContains: []string{"deprecated block addon_profile", ": use the top-level addon blocks instead"},
+		},
+		{
+			name: "provider function call in output",
+			finding: ValidationFinding{
+				ResourceType: "output.subnet_name",
+				Path:         "root",
+				Name:         "value",
+				Category:     CategoryFunction,
+				Kind:         KindOutput,
+				Detail:       "calls provider::azurerm::parse_id, which provider registry.terraform.io/hashicorp/azurerm does not define",
+			},
+			wantContains: []string{"output.subnet_name: property value in root calls provider::azurerm::parse_id"},
+		},
+		{
+			name: "provider function call in data source",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_client_config",
+				Path:         "root",
+				Name:         "tenant_id",
+				Category:     CategoryFunction,
+				Kind:         KindDataSource,
+				Detail:       "calls provider::azurerm::parse_id, which provider registry.terraform.io/hashicorp/azurerm does not define",
+			},
+			wantContains: []string{"azurerm_client_config: property tenant_id in root (data source) calls provider::azurerm::parse_id"},
+		},
+		{
+			name: "required property set to null",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_resource_group",
+				Path:         "root",
+				Name:         "location",
+				Category:     CategoryNull,
+				Required:     true,
+			},
+			wantContains: []string{"azurerm_resource_group: required property location in root (resource) is always null"},
+		},
+		{
+			name: "undeclared provider alias",
+			finding: ValidationFinding{
+				ResourceType: "azurerm_resource_group",
+				Path:         "root",
+				Name:         "provider",
+				Category:     CategoryProvider,
+				Detail:       "refers to azurerm.nope, which no configuration_aliases entry or provider block alias declares",
+			},
+			wantContains: []string{"azurerm_resource_group: property provider in root (resource) refers to azurerm.nope"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -261,6 +347,22 @@ func TestFilterDataSources(t *testing.T) {
 	}
 }
 
+func TestFilterFunctionCalls(t *testing.T) {
+	calls := []FunctionCall{
+		{Function: "parse_resource_id", Owner: "azurerm_subnet", Kind: KindResource},
+		{Function: "parse_resource_id", Owner: "azurerm_subnet", Kind: KindDataSource},
+		{Function: "parse_resource_id", Owner: "azurerm_virtual_network", Kind: KindDataSource},
+		{Function: "parse_resource_id", Owner: "locals", Kind: KindLocals},
+	}
+
+	filtered := filterFunctionCalls(calls, []string{"azurerm_subnet"}, []string{"azurerm_virtual_network"})
+
+	want := []FunctionCall{calls[1], calls[3]}
+	if diff := cmp.Diff(want, filtered); diff != "" {
+		t.Fatalf("filterFunctionCalls() mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestValidateEntitiesMissingProviderOrSchema(t *testing.T) {
 	validator := NewSchemaValidator(&SimpleLogger{})
 
@@ -270,7 +372,6 @@ func TestValidateEntitiesMissingProviderOrSchema(t *testing.T) {
 		map[string]ProviderConfig{}, // missing provider config, should log and skip
 		".",
 		"",
-		false,
 	)
 
 	if len(findings) != 0 {
@@ -291,7 +392,6 @@ func TestValidateEntitiesMissingProviderOrSchema(t *testing.T) {
 		map[string]ProviderConfig{"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"}},
 		".",
 		"",
-		false,
 	)
 
 	if len(findings) != 0 {
@@ -299,6 +399,37 @@ func TestValidateEntitiesMissingProviderOrSchema(t *testing.T) {
 	}
 }
 
+func TestResolveProvider(t *testing.T) {
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+		"hub":     {Source: "registry.terraform.io/hashicorp/azurerm", ConfigurationAliases: []string{"connectivity"}},
+		"cloud":   {Source: "registry.terraform.io/azure/azapi"},
+	}
+
+	tests := []struct {
+		name       string
+		entityType string
+		reference  string
+		want       string
+		wantOK     bool
+	}{
+		{name: "type prefix", entityType: "azurerm_resource_group", want: "registry.terraform.io/hashicorp/azurerm", wantOK: true},
+		{name: "meta-argument alias", entityType: "azurerm_resource_group", reference: "hub.connectivity", want: "registry.terraform.io/hashicorp/azurerm", wantOK: true},
+		{name: "custom local name", entityType: "azapi_resource", want: "registry.terraform.io/azure/azapi", wantOK: true},
+		{name: "unknown local name", entityType: "azurerm_resource_group", reference: "spoke.secondary", wantOK: false},
+		{name: "unknown prefix", entityType: "random_string", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolveProvider(tt.entityType, tt.reference, providers)
+			if ok != tt.wantOK || got.Source != tt.want {
+				t.Errorf("resolveProvider() = %q, %v, want %q, %v", got.Source, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestValidateBlocksMultipleStaticAndDynamic(t *testing.T) {
 	schema := &SchemaBlock{
 		Attributes: map[string]*SchemaAttribute{},
@@ -316,12 +447,12 @@ func TestValidateBlocksMultipleStaticAndDynamic(t *testing.T) {
 	}
 
 	bd := BlockData{
-		Properties:   map[string]bool{},
+		Properties:   map[string]ValueClass{},
 		StaticBlocks: map[string][]*ParsedBlock{},
 		DynamicBlocks: map[string]*ParsedBlock{
 			"child": {
 				Data: BlockData{
-					Properties:    map[string]bool{}, // missing required_attr
+					Properties:    map[string]ValueClass{}, // missing required_attr
 					StaticBlocks:  map[string][]*ParsedBlock{},
 					DynamicBlocks: map[string]*ParsedBlock{},
 					IgnoreChanges: nil,
@@ -332,12 +463,12 @@ func TestValidateBlocksMultipleStaticAndDynamic(t *testing.T) {
 
 	// Add multiple static children to exercise indexed paths
 	bd.StaticBlocks["child"] = []*ParsedBlock{
-		{Data: BlockData{Properties: map[string]bool{}}},
-		{Data: BlockData{Properties: map[string]bool{}}},
+		{Data: BlockData{Properties: map[string]ValueClass{}}},
+		{Data: BlockData{Properties: map[string]ValueClass{}}},
 	}
 
 	var findings []ValidationFinding
-	bd.validateBlocks("azurerm_virtual_network", "root", schema, nil, &findings)
+	bd.validateBlocks("azurerm_virtual_network", "root", schema, nil, &findings, &Coverage{})
 
 	if len(findings) != 3 {
 		t.Fatalf("expected findings for each static and dynamic child, got %d", len(findings))
@@ -367,11 +498,11 @@ func TestValidateTerraformSchemaSuccess(t *testing.T) {
 				Type: "azurerm_virtual_network",
 				Name: "test",
 				Data: BlockData{
-					Properties: map[string]bool{"location": true},
+					Properties: map[string]ValueClass{"location": ValueConstant},
 					StaticBlocks: map[string][]*ParsedBlock{
 						"subnet": {{
 							Data: BlockData{
-								Properties:    map[string]bool{"name": true},
+								Properties:    map[string]ValueClass{"name": ValueConstant},
 								StaticBlocks:  map[string][]*ParsedBlock{},
 								DynamicBlocks: map[string]*ParsedBlock{},
 								IgnoreChanges: nil,
@@ -498,7 +629,7 @@ func TestValidateProjectWithSubmodules(t *testing.T) {
 				Type: "azurerm_virtual_network",
 				Name: "test",
 				Data: BlockData{
-					Properties:    map[string]bool{}, // missing required attribute to trigger finding
+					Properties:    map[string]ValueClass{}, // missing required attribute to trigger finding
 					StaticBlocks:  make(map[string][]*ParsedBlock),
 					DynamicBlocks: make(map[string]*ParsedBlock),
 					IgnoreChanges: nil,
@@ -608,6 +739,303 @@ func writeFile(t *testing.T, path, content string) {
 	}
 }
 
+func TestValidatorRecordsValidatedEntities(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				ResourceSchemas: map[string]*ResourceSchema{
+					"azurerm_subnet": {
+						Block: &SchemaBlock{
+							Attributes: map[string]*SchemaAttribute{
+								"name": {Required: true},
+							},
+						},
+					},
+				},
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"azurerm_client_config": {
+						Block: &SchemaBlock{},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+	}
+
+	complete := NewBlockData()
+	complete.Properties["name"] = ValueConstant
+
+	validator := NewSchemaValidator(&SimpleLogger{})
+	validator.ValidateResources([]ParsedResource{
+		{Type: "azurerm_subnet", Name: "a", Data: NewBlockData()},
+		{Type: "azurerm_subnet", Name: "b", Data: complete},
+		{Type: "azurerm_unknown", Name: "skipped", Data: NewBlockData()},
+	}, schema, providers, "dir", "network")
+	validator.ValidateDataSources([]ParsedDataSource{
+		{Type: "azurerm_client_config", Name: "current", Data: NewBlockData()},
+	}, schema, providers, "dir", "network")
+
+	entities := validator.Entities()
+	if len(entities) != 3 {
+		t.Fatalf("expected 3 validated entities, got %d: %+v", len(entities), entities)
+	}
+
+	if entities[0].Name != "a" || len(entities[0].Findings) != 1 || entities[0].Findings[0].SubmoduleName != "network" {
+		t.Fatalf("unexpected first entity: %+v", entities[0])
+	}
+	if entities[1].Name != "b" || len(entities[1].Findings) != 0 {
+		t.Fatalf("unexpected second entity: %+v", entities[1])
+	}
+	if entities[2].Kind != KindDataSource || entities[2].Name != "current" {
+		t.Fatalf("unexpected data source entity: %+v", entities[2])
+	}
+}
+
+func TestValidatorChecksBlocksInIgnoreChanges(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				ResourceSchemas: map[string]*ResourceSchema{
+					"azurerm_linux_web_app": {
+						Block: &SchemaBlock{
+							Attributes: map[string]*SchemaAttribute{
+								"location": {Type: cty.String, Required: true},
+							},
+							BlockTypes: map[string]*SchemaBlockType{
+								"site_config": {
+									Nesting: "list",
+									Block: &SchemaBlock{
+										Attributes: map[string]*SchemaAttribute{
+											"worker_count":      {Type: cty.Number, Optional: true},
+											"health_check_path": {Type: cty.String, Required: true},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+	}
+
+	tests := []struct {
+		name   string
+		ignore string
+		want   []string
+	}{
+		{
+			name:   "block entry",
+			ignore: "site_config",
+			want:   []string{"missing:location", "type:site_config.worker_count", "unknown:site_config.always_onn"},
+		},
+		{
+			name:   "all",
+			ignore: "all",
+			want:   []string{"type:site_config.worker_count", "unknown:site_config.always_onn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(`
+site_config {
+  always_onn   = true
+  worker_count = "two"
+}
+
+lifecycle {
+  ignore_changes = [`+tt.ignore+`]
+}
+`), "main.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("failed to parse HCL: %v", diags)
+			}
+
+			validator := NewSchemaValidator(&SimpleLogger{})
+			findings := validator.ValidateResources([]ParsedResource{
+				{Type: "azurerm_linux_web_app", Name: "app", Data: ParseSyntaxBody(file.Body.(*hclsyntax.Body)).Data},
+			}, schema, providers, "dir", "")
+
+			var got []string
+			for _, f := range findings {
+				got = append(got, string(f.Category)+":"+findingPath(f))
+			}
+			slices.Sort(got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("findings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidatorIdentifiesCheckDataSources(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				DataSourceSchemas: map[string]*ResourceSchema{
+					"azurerm_linux_web_app": {
+						Block: &SchemaBlock{
+							Attributes: map[string]*SchemaAttribute{
+								"name": {Required: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+	}
+
+	validator := NewSchemaValidator(&SimpleLogger{})
+	findings := validator.ValidateDataSources([]ParsedDataSource{
+		{Type: "azurerm_linux_web_app", Name: "app", Check: "endpoint", Data: NewBlockData()},
+	}, schema, providers, "dir", "")
+
+	if len(findings) != 1 || findings[0].Check != "endpoint" || findings[0].Kind != KindDataSource {
+		t.Fatalf("expected one finding tagged with the check name, got %+v", findings)
+	}
+	if entities := validator.Entities(); len(entities) != 1 || entities[0].Check != "endpoint" {
+		t.Fatalf("expected the entity to carry the check name, got %+v", entities)
+	}
+}
+
+func TestValidatorUsesEphemeralResourceSchemas(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				ResourceSchemas: map[string]*ResourceSchema{
+					"azurerm_key_vault_secret": {
+						Block: &SchemaBlock{Attributes: map[string]*SchemaAttribute{"value": {Required: true}}},
+					},
+				},
+				EphemeralResourceSchemas: map[string]*ResourceSchema{
+					"azurerm_key_vault_secret": {
+						Block: &SchemaBlock{Attributes: map[string]*SchemaAttribute{"key_vault_id": {Required: true}}},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+	}
+
+	validator := NewSchemaValidator(&SimpleLogger{})
+	findings := validator.ValidateResources([]ParsedResource{
+		{Type: "azurerm_key_vault_secret", Name: "password", Kind: KindEphemeral, Data: NewBlockData()},
+	}, schema, providers, "dir", "")
+
+	if len(findings) != 1 || findings[0].Name != "key_vault_id" || findings[0].Kind != KindEphemeral {
+		t.Fatalf("expected the ephemeral schema to be used, got %+v", findings)
+	}
+	if got := FormatFinding(findings[0]); !contains(got, "(ephemeral resource)") {
+		t.Fatalf("message should name the entity kind, got %q", got)
+	}
+	if entities := validator.Entities(); len(entities) != 1 || entities[0].Kind != KindEphemeral {
+		t.Fatalf("unexpected entities %+v", entities)
+	}
+}
+
+func TestValidatorValidatesProviderBlocks(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				Provider: &ResourceSchema{
+					Block: &SchemaBlock{
+						Attributes: map[string]*SchemaAttribute{
+							"subscription_id": {Optional: true},
+						},
+						BlockTypes: map[string]*SchemaBlockType{
+							"features": {Nesting: "list", MinItems: 1, MaxItems: 1, Block: &SchemaBlock{}},
+						},
+					},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm"},
+	}
+
+	data := NewBlockData()
+	data.Properties["skip_provider_registration"] = ValueConstant
+
+	validator := NewSchemaValidator(&SimpleLogger{})
+	findings := validator.ValidateResources([]ParsedResource{
+		{Type: "azurerm", Name: "connectivity", Kind: KindProvider, Provider: "azurerm", Data: data},
+	}, schema, providers, "dir", "")
+
+	got := map[string]FindingCategory{}
+	for _, f := range findings {
+		if f.Kind != KindProvider || f.ResourceType != "azurerm" {
+			t.Fatalf("unexpected finding %+v", f)
+		}
+		got[f.Name] = f.Category
+	}
+
+	want := map[string]FindingCategory{
+		"features":                   CategoryMissing,
+		"subscription_id":            CategoryMissing,
+		"skip_provider_registration": CategoryUnknown,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("provider findings mismatch (-want +got):\n%s", diff)
+	}
+
+	if entities := validator.Entities(); len(entities) != 1 || entities[0].Kind.Address(entities[0].Type, entities[0].Name) != "provider.azurerm.connectivity" {
+		t.Fatalf("unexpected entities %+v", entities)
+	}
+}
+
+func TestValidatorReportsUndeclaredProviderAliases(t *testing.T) {
+	schema := TerraformSchema{
+		ProviderSchemas: map[string]*ProviderSchema{
+			"registry.terraform.io/hashicorp/azurerm": {
+				ResourceSchemas: map[string]*ResourceSchema{
+					"azurerm_resource_group": {Block: &SchemaBlock{}},
+				},
+			},
+		},
+	}
+	providers := map[string]ProviderConfig{
+		"azurerm": {Source: "registry.terraform.io/hashicorp/azurerm", Aliases: []string{"secondary"}},
+		"hub":     {Source: "registry.terraform.io/hashicorp/azurerm", ConfigurationAliases: []string{"connectivity"}},
+	}
+
+	validator := NewSchemaValidator(&SimpleLogger{})
+	findings := validator.ValidateResources([]ParsedResource{
+		{Type: "azurerm_resource_group", Name: "default", Provider: "azurerm", Data: NewBlockData()},
+		{Type: "azurerm_resource_group", Name: "configured", Provider: "hub.connectivity", Data: NewBlockData()},
+		{Type: "azurerm_resource_group", Name: "block", Provider: "azurerm.secondary", Data: NewBlockData()},
+		{Type: "azurerm_resource_group", Name: "undeclared", Provider: "azurerm.nope", Data: NewBlockData()},
+		{Type: "azurerm_resource_group", Name: "wrong_provider", Provider: "hub.secondary", Data: NewBlockData()},
+	}, schema, providers, "dir", "")
+
+	var details []string
+	for _, f := range findings {
+		if f.Category != CategoryProvider || f.Name != "provider" {
+			t.Fatalf("unexpected finding %+v", f)
+		}
+		details = append(details, f.Detail)
+	}
+
+	want := []string{
+		"refers to azurerm.nope, which no configuration_aliases entry or provider block alias declares",
+		"refers to hub.secondary, which no configuration_aliases entry or provider block alias declares",
+	}
+	if diff := cmp.Diff(want, details); diff != "" {
+		t.Fatalf("provider alias findings mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestNewSchemaValidator(t *testing.T) {
 	logger := &SimpleLogger{}
 	validator := NewSchemaValidator(logger)